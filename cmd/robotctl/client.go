@@ -0,0 +1,41 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+
+	"github.com/RadiumByte/Robot-Server/pkg/client"
+)
+
+// newClient constructs API client from the profile
+func newClient(profile *Profile) (*client.Client, error) {
+	opts := client.Options{Token: profile.Token}
+
+	if profile.CA != "" || profile.Cert != "" {
+		opts.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+
+		if profile.CA != "" {
+			body, err := ioutil.ReadFile(profile.CA)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(body) {
+				return nil, errors.New(profile.CA + ": no PEM certificates found")
+			}
+			opts.TLS.RootCAs = pool
+		}
+
+		if profile.Cert != "" {
+			cert, err := tls.LoadX509KeyPair(profile.Cert, profile.Key)
+			if err != nil {
+				return nil, err
+			}
+			opts.TLS.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	return client.New(profile.Server, opts)
+}
@@ -0,0 +1,742 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RadiumByte/Robot-Server/pkg/client"
+)
+
+// nameOf returns name by its number
+func nameOf(names []string, index int) string {
+	if index >= 0 && index < len(names) {
+		return names[index]
+	}
+	return strconv.Itoa(index)
+}
+
+// modeOf returns name of the driving mode
+func modeOf(manual bool) string {
+	if manual {
+		return "manual"
+	}
+	return "auto"
+}
+
+// printResult reports successful command
+func (ctx *session) printResult(command string) error {
+	if ctx.opts.output == outputJSON {
+		return printJSON(os.Stdout, map[string]interface{}{"command": command, "ok": true})
+	}
+	fmt.Println(command + ": ok")
+	return nil
+}
+
+// runStatus shows state of the server
+func runStatus(ctx *session) error {
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	status, err := api.Status(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if ctx.opts.output == outputJSON {
+		return printJSON(os.Stdout, status)
+	}
+
+	preflight := "not run"
+	if status.Preflight != nil {
+		preflight = "failed"
+		if status.Preflight.Passed {
+			preflight = "passed"
+		}
+	}
+
+	rows := [][]string{
+		{"FIELD", "VALUE"},
+		{"mode", modeOf(status.IsManual)},
+		{"blocked", strconv.FormatBool(status.IsBlocked)},
+		{"sign", nameOf(client.Signs, status.CascadeType)},
+		{"overlay", nameOf(client.Overlays, status.Overlay)},
+		{"acquisition", fmt.Sprintf("%s (%d/%d)", status.Acquisition.State,
+			status.Acquisition.Confirmed, status.Acquisition.Required)},
+		{"preflight", preflight},
+		{"throttle", strconv.Itoa(status.Telemetry.Throttle)},
+		{"steering", strconv.Itoa(status.Telemetry.Steering)},
+		{"fps", strconv.FormatFloat(status.Telemetry.FPS, 'f', 1, 64)},
+		{"failures", strconv.Itoa(status.Telemetry.FailureCounter)},
+		{"has target", strconv.FormatBool(status.Telemetry.HasTarget)},
+	}
+
+	var stages []string
+	for stage := range status.Stages {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		counter := status.Stages[stage]
+		rows = append(rows, []string{"stage " + stage,
+			fmt.Sprintf("passed %d, failed %d", counter.Passed, counter.Failed)})
+	}
+
+	return printTable(os.Stdout, rows)
+}
+
+// runSimple returns command, which sends a fixed command
+func runSimple(command string) func(ctx *session) error {
+	return func(ctx *session) error {
+		api, err := ctx.client()
+		if err != nil {
+			return err
+		}
+		if err := api.Command(context.Background(), command); err != nil {
+			return err
+		}
+		return ctx.printResult(command)
+	}
+}
+
+// runMode switches driving mode
+func runMode(ctx *session) error {
+	if len(ctx.args) != 1 || (ctx.args[0] != "auto" && ctx.args[0] != "manual") {
+		return errors.New("usage: robotctl mode auto|manual")
+	}
+	return runSimple(ctx.args[0])(ctx)
+}
+
+// runSign selects traffic sign
+func runSign(ctx *session) error {
+	if len(ctx.args) != 1 {
+		return errors.New("usage: robotctl sign " + strings.Join(client.Signs, "|"))
+	}
+	for _, sign := range client.Signs {
+		if ctx.args[0] == sign {
+			return runSimple(sign + "sign")(ctx)
+		}
+	}
+	return errors.New("unknown sign: " + ctx.args[0])
+}
+
+// runDrive sends manual steering and throttle
+func runDrive(ctx *session) error {
+	flags := flag.NewFlagSet("drive", flag.ContinueOnError)
+	steer := flags.Int("steer", 50, "steering 0..100, 50 is straight")
+	throttle := flags.Int("throttle", 0, "throttle -100..100, negative is backward")
+	if err := flags.Parse(ctx.args); err != nil {
+		return err
+	}
+
+	// Only explicitly set values are sent
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if !set["steer"] && !set["throttle"] {
+		return errors.New("usage: robotctl drive [-steer N] [-throttle N]")
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	var sent []string
+	if set["steer"] {
+		if err := api.Steer(context.Background(), *steer); err != nil {
+			return err
+		}
+		sent = append(sent, "steer="+strconv.Itoa(*steer))
+	}
+	if set["throttle"] {
+		if err := api.Throttle(context.Background(), *throttle); err != nil {
+			return err
+		}
+		sent = append(sent, "throttle="+strconv.Itoa(*throttle))
+	}
+	return ctx.printResult(strings.Join(sent, " "))
+}
+
+// readSettings collects settings, which can be changed by config set
+func readSettings(api *client.Client) (map[string]string, error) {
+	status, err := api.Status(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	levels, err := api.LogLevels(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]string{
+		"mode":    modeOf(status.IsManual),
+		"sign":    nameOf(client.Signs, status.CascadeType),
+		"overlay": nameOf(client.Overlays, status.Overlay),
+	}
+	for component, level := range levels {
+		res["log."+component] = level
+	}
+	return res, nil
+}
+
+// writeSetting changes a single setting
+func writeSetting(api *client.Client, key string, value string) error {
+	ctx := context.Background()
+
+	switch {
+	case key == "mode":
+		if value != "auto" && value != "manual" {
+			return errors.New("mode must be auto or manual")
+		}
+		return api.SetManual(ctx, value == "manual")
+	case key == "sign":
+		return api.SetSign(ctx, value)
+	case key == "overlay":
+		return api.SetOverlay(ctx, value)
+	case strings.HasPrefix(key, "log."):
+		return api.SetLogLevel(ctx, strings.TrimPrefix(key, "log."), value)
+	}
+	return errors.New("unknown setting: " + key)
+}
+
+// runConfig reads or changes settings of the server
+func runConfig(ctx *session) error {
+	if len(ctx.args) == 0 {
+		return errors.New("usage: robotctl config get [key] | config set <key> <value>")
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	switch ctx.args[0] {
+	case "get":
+		settings, err := readSettings(api)
+		if err != nil {
+			return err
+		}
+		if len(ctx.args) > 1 {
+			value, ok := settings[ctx.args[1]]
+			if !ok {
+				return errors.New("unknown setting: " + ctx.args[1])
+			}
+			settings = map[string]string{ctx.args[1]: value}
+		}
+
+		if ctx.opts.output == outputJSON {
+			return printJSON(os.Stdout, settings)
+		}
+		var keys []string
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		rows := [][]string{{"KEY", "VALUE"}}
+		for _, key := range keys {
+			rows = append(rows, []string{key, settings[key]})
+		}
+		return printTable(os.Stdout, rows)
+
+	case "set":
+		if len(ctx.args) != 3 {
+			return errors.New("usage: robotctl config set <key> <value>")
+		}
+		if err := writeSetting(api, ctx.args[1], ctx.args[2]); err != nil {
+			return err
+		}
+		return ctx.printResult(ctx.args[1] + "=" + ctx.args[2])
+	}
+	return errors.New("unknown config command: " + ctx.args[0])
+}
+
+// summary returns short description of the event for table output
+func summary(event *client.Event) string {
+	switch event.Type {
+	case client.EventFrame:
+		var frame client.FrameEvent
+		event.Decode(&frame)
+		target := "no target"
+		if frame.Target != nil {
+			target = "target"
+		}
+		return fmt.Sprintf("frame %d: %d raw, %s, throttle %d, steering %d",
+			frame.Frame, len(frame.Raw), target, frame.Throttle, frame.Steering)
+	case client.EventCommand:
+		var command client.CommandEvent
+		event.Decode(&command)
+		return command.Command
+	case client.EventMode:
+		var mode client.ModeEvent
+		event.Decode(&mode)
+		return fmt.Sprintf("mode %s, blocked %v, sign %s", modeOf(mode.Manual), mode.Blocked, mode.Cascade)
+	}
+
+	var message client.MessageEvent
+	if err := event.Decode(&message); err == nil && message.Message != "" {
+		return message.Message
+	}
+	return string(event.Data)
+}
+
+// runWatch prints events until interrupted
+func runWatch(ctx *session) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	types := flags.String("types", "", "comma separated event types: frame, command, mode, error, watchdog")
+	if err := flags.Parse(ctx.args); err != nil {
+		return err
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	var filter []string
+	if *types != "" {
+		filter = strings.Split(*types, ",")
+	}
+	stream, err := api.Events(context.Background(), filter...)
+	if err != nil {
+		return err
+	}
+
+	// interrupted is closed before the stream, so the following read error is not reported
+	interrupts := make(chan os.Signal, 1)
+	interrupted := make(chan struct{})
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		<-interrupts
+		close(interrupted)
+		stream.Close()
+	}()
+
+	for {
+		event, err := stream.Next()
+		if err != nil {
+			select {
+			case <-interrupted:
+				return nil
+			default:
+				stream.Close()
+				if err == io.EOF {
+					return errors.New("event stream closed by server")
+				}
+				return err
+			}
+		}
+
+		if ctx.opts.output == outputJSON {
+			if err := printCompactJSON(os.Stdout, event); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Printf("%s  %-8s  %s\n", event.Time.Format("15:04:05.000"), event.Type, summary(event))
+	}
+}
+
+// runSnapshot saves the latest frame, format is chosen by extension of the file
+func runSnapshot(ctx *session) error {
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	// Flags of the command are parsed after the global ones, so -o here is the file
+	var output string
+	flags.StringVar(&output, "o", "snapshot.jpg", "output file, .jpg or .png")
+	flags.StringVar(&output, "out", "snapshot.jpg", "alias of -o")
+	raw := flags.Bool("raw", false, "frame without overlay")
+	width := flags.Int("width", 0, "resize to width")
+	height := flags.Int("height", 0, "resize to height")
+	if err := flags.Parse(ctx.args); err != nil {
+		return err
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	opts := client.SnapshotOptions{
+		Raw:    *raw,
+		PNG:    strings.EqualFold(filepath.Ext(output), ".png"),
+		Width:  *width,
+		Height: *height,
+	}
+	frame, err := api.Snapshot(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(output, frame, 0644); err != nil {
+		return err
+	}
+
+	if ctx.opts.output == outputJSON {
+		return printJSON(os.Stdout, map[string]interface{}{"file": output, "bytes": len(frame)})
+	}
+	fmt.Printf("%s: %d bytes\n", output, len(frame))
+	return nil
+}
+
+// runCapture starts capture jobs on the server and lists them
+func runCapture(ctx *session) error {
+	if len(ctx.args) == 0 {
+		return errors.New("usage: robotctl capture start -frames N [-format png|jpeg] | capture list")
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	switch ctx.args[0] {
+	case "start":
+		flags := flag.NewFlagSet("capture start", flag.ContinueOnError)
+		frames := flags.Int("frames", 100, "number of frames to save")
+		format := flags.String("format", "png", "image format: png or jpeg")
+		if err := flags.Parse(ctx.args[1:]); err != nil {
+			return err
+		}
+
+		status, err := api.StartCapture(context.Background(), *frames, *format)
+		if err != nil {
+			return err
+		}
+		return printCaptures(ctx, []client.CaptureStatus{*status})
+
+	case "list":
+		jobs, err := api.Captures(context.Background())
+		if err != nil {
+			return err
+		}
+		return printCaptures(ctx, jobs)
+	}
+	return errors.New("unknown capture command: " + ctx.args[0])
+}
+
+// printCaptures prints capture jobs as table or JSON
+func printCaptures(ctx *session, jobs []client.CaptureStatus) error {
+	if ctx.opts.output == outputJSON {
+		return printJSON(os.Stdout, jobs)
+	}
+
+	rows := [][]string{{"ID", "STATE", "SAVED", "SKIPPED", "DIR", "ERROR"}}
+	for _, job := range jobs {
+		rows = append(rows, []string{
+			job.ID,
+			job.State,
+			fmt.Sprintf("%d/%d", job.Saved, job.Frames),
+			strconv.Itoa(job.Skipped),
+			job.Dir,
+			job.Error,
+		})
+	}
+	return printTable(os.Stdout, rows)
+}
+
+// runRecord starts and stops video recording on the server
+func runRecord(ctx *session) error {
+	if len(ctx.args) == 0 {
+		return errors.New("usage: robotctl record start [-max-duration D] [-fps N] | record stop | record status")
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	var status *client.RecordingStatus
+	switch ctx.args[0] {
+	case "start":
+		flags := flag.NewFlagSet("record start", flag.ContinueOnError)
+		maxDuration := flags.Duration("max-duration", 0, "length of a single file, server default if not set")
+		fps := flags.Float64("fps", 0, "frame rate of video files, server default if not set")
+		if err := flags.Parse(ctx.args[1:]); err != nil {
+			return err
+		}
+		status, err = api.StartRecording(context.Background(), client.RecordingOptions{MaxDuration: *maxDuration, FPS: *fps})
+
+	case "stop":
+		status, err = api.StopRecording(context.Background())
+
+	case "status":
+		status, err = api.Recording(context.Background())
+
+	default:
+		return errors.New("unknown record command: " + ctx.args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if ctx.opts.output == outputJSON {
+		return printJSON(os.Stdout, status)
+	}
+
+	rows := [][]string{
+		{"FIELD", "VALUE"},
+		{"recording", strconv.FormatBool(status.Recording)},
+		{"file", status.File},
+		{"frames", strconv.Itoa(status.Frames)},
+		{"skipped", strconv.Itoa(status.Skipped)},
+		{"fps", strconv.FormatFloat(status.FPS, 'f', -1, 64)},
+		{"max duration", (time.Duration(status.MaxDuration) * time.Second).String()},
+	}
+	for _, file := range status.Files {
+		rows = append(rows, []string{"written", file})
+	}
+	if status.Error != "" {
+		rows = append(rows, []string{"error", status.Error})
+	}
+	return printTable(os.Stdout, rows)
+}
+
+// runCamera shows and changes capture properties of the camera
+func runCamera(ctx *session) error {
+	if len(ctx.args) == 0 {
+		return errors.New("usage: robotctl camera get | camera set <name>=<value>...")
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	switch ctx.args[0] {
+	case "get":
+		state, err := api.Camera(context.Background())
+		if err != nil {
+			return err
+		}
+		if ctx.opts.output == outputJSON {
+			return printJSON(os.Stdout, state)
+		}
+
+		var names []string
+		for name := range state.Properties {
+			names = append(names, name)
+		}
+		for name := range state.Profile {
+			if _, ok := state.Properties[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		rows := [][]string{{"PROPERTY", "VALUE", "PROFILE"}}
+		for _, name := range names {
+			value := "-"
+			if actual, ok := state.Properties[name]; ok {
+				value = strconv.FormatFloat(actual, 'f', -1, 64)
+			}
+			saved := "-"
+			if profile, ok := state.Profile[name]; ok {
+				saved = strconv.FormatFloat(profile, 'f', -1, 64)
+			}
+			rows = append(rows, []string{name, value, saved})
+		}
+		if !state.Open {
+			fmt.Printf("%s is not open\n", state.Device)
+		}
+		return printTable(os.Stdout, rows)
+
+	case "set":
+		if len(ctx.args) < 2 {
+			return errors.New("usage: robotctl camera set <name>=<value>...")
+		}
+		properties := make(map[string]float64)
+		for _, arg := range ctx.args[1:] {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) != 2 {
+				return errors.New("property must be name=value: " + arg)
+			}
+			value, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return errors.New("invalid value of " + parts[0] + ": " + parts[1])
+			}
+			properties[parts[0]] = value
+		}
+
+		update, err := api.SetCamera(context.Background(), properties)
+		if err != nil {
+			return err
+		}
+		if ctx.opts.output == outputJSON {
+			if err := printJSON(os.Stdout, update); err != nil {
+				return err
+			}
+		} else {
+			rows := [][]string{{"PROPERTY", "REQUESTED", "ACTUAL", "APPLIED"}}
+			for _, property := range update.Properties {
+				rows = append(rows, []string{
+					property.Name,
+					strconv.FormatFloat(property.Requested, 'f', -1, 64),
+					strconv.FormatFloat(property.Actual, 'f', -1, 64),
+					strconv.FormatBool(property.Applied),
+				})
+			}
+			if err := printTable(os.Stdout, rows); err != nil {
+				return err
+			}
+		}
+		if !update.Applied {
+			return errors.New("camera rejected some values")
+		}
+		return nil
+	}
+	return errors.New("unknown camera command: " + ctx.args[0])
+}
+
+// runMasks shows and replaces zones of the frame
+func runMasks(ctx *session) error {
+	if len(ctx.args) == 0 {
+		return errors.New("usage: robotctl masks get | masks set <file.json> | masks clear")
+	}
+
+	api, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	var masks *client.Masks
+	switch ctx.args[0] {
+	case "get":
+		masks, err = api.Masks(context.Background())
+
+	case "set":
+		if len(ctx.args) != 2 {
+			return errors.New("usage: robotctl masks set <file.json>")
+		}
+		body, err := ioutil.ReadFile(ctx.args[1])
+		if err != nil {
+			return err
+		}
+		var request client.Masks
+		if err := json.Unmarshal(body, &request); err != nil {
+			return errors.New(ctx.args[1] + ": " + err.Error())
+		}
+		masks, err = api.SetMasks(context.Background(), request)
+		if err != nil {
+			return err
+		}
+
+	case "clear":
+		masks, err = api.SetMasks(context.Background(), client.Masks{})
+
+	default:
+		return errors.New("unknown masks command: " + ctx.args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if ctx.opts.output == outputJSON {
+		return printJSON(os.Stdout, masks)
+	}
+
+	rows := [][]string{{"KIND", "NAME", "POLYGON"}}
+	addZones := func(kind string, zones []client.Zone) {
+		for _, zone := range zones {
+			var points []string
+			for _, point := range zone.Polygon {
+				points = append(points, fmt.Sprintf("%.2f,%.2f", point.X, point.Y))
+			}
+			rows = append(rows, []string{kind, zone.Name, strings.Join(points, " ")})
+		}
+	}
+	addZones("include", masks.Include)
+	addZones("exclude", masks.Exclude)
+	return printTable(os.Stdout, rows)
+}
+
+// runProfile manages profiles of servers
+func runProfile(ctx *session) error {
+	if len(ctx.args) == 0 {
+		return errors.New("usage: robotctl profile list | use <name> | set <name> [flags]")
+	}
+
+	switch ctx.args[0] {
+	case "list":
+		// Tokens are never printed, only their presence
+		type profileView struct {
+			Name    string `json:"name"`
+			Current bool   `json:"current"`
+			Server  string `json:"server"`
+			Token   bool   `json:"token"`
+			MTLS    bool   `json:"mtls"`
+		}
+		var views []profileView
+		for _, name := range ctx.config.names() {
+			profile := ctx.config.Profiles[name]
+			views = append(views, profileView{Name: name, Current: name == ctx.config.Current,
+				Server: profile.Server, Token: profile.Token != "", MTLS: profile.Cert != ""})
+		}
+
+		if ctx.opts.output == outputJSON {
+			return printJSON(os.Stdout, views)
+		}
+		rows := [][]string{{"CURRENT", "NAME", "SERVER", "TOKEN", "MTLS"}}
+		for _, view := range views {
+			current := ""
+			if view.Current {
+				current = "*"
+			}
+			rows = append(rows, []string{current, view.Name, view.Server,
+				strconv.FormatBool(view.Token), strconv.FormatBool(view.MTLS)})
+		}
+		return printTable(os.Stdout, rows)
+
+	case "use":
+		if len(ctx.args) != 2 {
+			return errors.New("usage: robotctl profile use <name>")
+		}
+		if _, ok := ctx.config.Profiles[ctx.args[1]]; !ok {
+			return errors.New("unknown profile: " + ctx.args[1])
+		}
+		ctx.config.Current = ctx.args[1]
+		return ctx.config.save(ctx.configPath)
+
+	case "set":
+		if len(ctx.args) < 2 {
+			return errors.New("usage: robotctl profile set <name> [flags]")
+		}
+		name := ctx.args[1]
+		profile, ok := ctx.config.Profiles[name]
+		if !ok {
+			profile = &Profile{Server: defaultServer}
+		}
+
+		flags := flag.NewFlagSet("profile set", flag.ContinueOnError)
+		flags.StringVar(&profile.Server, "server", profile.Server, "server URL")
+		flags.StringVar(&profile.Token, "token", profile.Token, "API token")
+		flags.StringVar(&profile.CA, "ca", profile.CA, "PEM CA of the server")
+		flags.StringVar(&profile.Cert, "cert", profile.Cert, "PEM client certificate for mutual TLS")
+		flags.StringVar(&profile.Key, "key", profile.Key, "PEM client key for mutual TLS")
+		if err := flags.Parse(ctx.args[2:]); err != nil {
+			return err
+		}
+
+		ctx.config.Profiles[name] = profile
+		if ctx.config.Current == "" {
+			ctx.config.Current = name
+		}
+		return ctx.config.save(ctx.configPath)
+	}
+	return errors.New("unknown profile command: " + ctx.args[0])
+}
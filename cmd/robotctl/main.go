@@ -0,0 +1,154 @@
+// robotctl is a command-line client of the Robot Server API
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RadiumByte/Robot-Server/pkg/client"
+)
+
+const usage = `Usage: robotctl [flags] <command> [args]
+
+Commands:
+  status                           show state of the server
+  mode auto|manual                 switch driving mode
+  halt                             stop the car and block it
+  go                               unblock the car
+  sign stop|circle|yield           select traffic sign to follow
+  drive [-steer N] [-throttle N]   drive manually, steer 0..100, throttle -100..100
+  config get [key]                 show settings: overlay, sign, mode, log.<component>
+  config set <key> <value>         change a setting
+  watch [-types frame,command]     tail the event stream
+  snapshot [-raw] -o file.jpg|png  save latest frame, -width/-height resize it
+  capture start -frames N          save next frames with detections on the server
+  capture list                     show recent capture jobs
+  record start [-max-duration D]   record autopilot view to video files on the server
+  record stop|status               stop recording or show its state
+  camera get                       show camera properties and saved profile
+  camera set <name>=<value>...     change camera properties, e.g. exposure=-6 gain=10
+  preprocess get                   show preprocessing steps applied before detection
+  preprocess set <step.field>=<v>  change steps, e.g. gamma.enabled=true gamma.gamma=1.8
+  masks get                        show include and exclude zones
+  masks set <file.json>|clear      replace zones from JSON file or remove them
+  quality get                      show thresholds of the frame quality gate
+  quality set <field>=<value>      change thresholds, e.g. min_sharpness=40 max_hold_ms=300
+  acquisition get                  show confirmation window of the target
+  acquisition set <field>=<value>  change confirmation window, e.g. required=2 window=4
+  search get                       show search window around the previous target
+  search set <field>=<value>       change search window, e.g. enabled=true full_scan_every=5
+  shape get                        show contour geometry thresholds of the signs
+  shape set <sign.field>=<value>   change thresholds, e.g. stop.enabled=false yield.epsilon=0.05
+  grouping get                     show grouping of overlapping cascade hits
+  grouping set <field>=<value>     change grouping, e.g. use_group_rectangles=true group_threshold=2
+  profile list                     show configured servers
+  profile use <name>               make profile current
+  profile set <name> -server URL   create or change profile
+
+Flags:
+`
+
+// options are global flags shared by all commands
+type options struct {
+	profile string
+	server  string
+	token   string
+	output  string
+}
+
+// session is passed to every command
+type session struct {
+	opts       options
+	config     *Config
+	configPath string
+	args       []string
+}
+
+// client constructs API client from selected profile and flags
+func (ctx *session) client() (*client.Client, error) {
+	profile, err := ctx.config.resolve(ctx.opts.profile)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.opts.server != "" {
+		profile.Server = ctx.opts.server
+	}
+	if ctx.opts.token != "" {
+		profile.Token = ctx.opts.token
+	}
+	return newClient(profile)
+}
+
+// commands maps command names to their implementations
+var commands = map[string]func(ctx *session) error{
+	"status":      runStatus,
+	"mode":        runMode,
+	"halt":        runSimple("halt"),
+	"go":          runSimple("go"),
+	"sign":        runSign,
+	"drive":       runDrive,
+	"config":      runConfig,
+	"watch":       runWatch,
+	"snapshot":    runSnapshot,
+	"capture":     runCapture,
+	"record":      runRecord,
+	"camera":      runCamera,
+	"preprocess":  runParams(preprocessParams),
+	"masks":       runMasks,
+	"quality":     runParams(qualityParams),
+	"search":      runParams(searchParams),
+	"acquisition": runParams(acquisitionParams),
+	"shape":       runParams(shapeParams),
+	"grouping":    runParams(groupingParams),
+	"profile":     runProfile,
+}
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	opts := options{}
+	flag.StringVar(&opts.profile, "profile", os.Getenv("ROBOTCTL_PROFILE"), "profile of the server, current profile by default")
+	flag.StringVar(&opts.server, "server", "", "server URL, overrides profile")
+	flag.StringVar(&opts.token, "token", os.Getenv("ROBOTCTL_TOKEN"), "API token, overrides profile")
+	flag.StringVar(&opts.output, "o", outputTable, "output format: table or json")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if opts.output != outputTable && opts.output != outputJSON {
+		fmt.Fprintln(os.Stderr, "unknown output format:", opts.output)
+		return 2
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		return 2
+	}
+
+	command, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "unknown command:", flag.Arg(0))
+		flag.Usage()
+		return 2
+	}
+
+	ctx := &session{opts: opts, args: flag.Args()[1:]}
+	ctx.configPath = configPath()
+	config, err := loadConfig(ctx.configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ctx.config = config
+
+	if err := command(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(err.Error()))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/RadiumByte/Robot-Server/pkg/client"
+)
+
+// paramsCommand shows and changes tunable params of an autopilot stage
+type paramsCommand struct {
+	name string
+	get  func(ctx context.Context, api *client.Client) (interface{}, error)
+	set  func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error)
+}
+
+// Stages of the autopilot with tunable params
+var (
+	preprocessParams = paramsCommand{
+		name: "preprocess",
+		get: func(ctx context.Context, api *client.Client) (interface{}, error) {
+			return api.Preprocess(ctx)
+		},
+		set: func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error) {
+			return api.SetPreprocess(ctx, patch)
+		},
+	}
+	qualityParams = paramsCommand{
+		name: "quality",
+		get: func(ctx context.Context, api *client.Client) (interface{}, error) {
+			return api.Quality(ctx)
+		},
+		set: func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error) {
+			return api.SetQuality(ctx, patch)
+		},
+	}
+	acquisitionParams = paramsCommand{
+		name: "acquisition",
+		get: func(ctx context.Context, api *client.Client) (interface{}, error) {
+			return api.Acquisition(ctx)
+		},
+		set: func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error) {
+			return api.SetAcquisition(ctx, patch)
+		},
+	}
+	searchParams = paramsCommand{
+		name: "search",
+		get: func(ctx context.Context, api *client.Client) (interface{}, error) {
+			return api.Search(ctx)
+		},
+		set: func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error) {
+			return api.SetSearch(ctx, patch)
+		},
+	}
+	shapeParams = paramsCommand{
+		name: "shape",
+		get: func(ctx context.Context, api *client.Client) (interface{}, error) {
+			return api.Shape(ctx)
+		},
+		set: func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error) {
+			return api.SetShape(ctx, patch)
+		},
+	}
+	groupingParams = paramsCommand{
+		name: "grouping",
+		get: func(ctx context.Context, api *client.Client) (interface{}, error) {
+			return api.Grouping(ctx)
+		},
+		set: func(ctx context.Context, api *client.Client, patch map[string]interface{}) (interface{}, error) {
+			return api.SetGrouping(ctx, patch)
+		},
+	}
+)
+
+// runParams returns command "<name> get | <name> set <field>=<value>..."
+// Only given fields are changed, nested fields are selected by path, e.g. gamma.enabled=true
+func runParams(command paramsCommand) func(ctx *session) error {
+	return func(ctx *session) error {
+		usage := "usage: robotctl " + command.name + " get | " + command.name + " set <field>=<value>..."
+		if len(ctx.args) == 0 {
+			return errors.New(usage)
+		}
+
+		api, err := ctx.client()
+		if err != nil {
+			return err
+		}
+
+		var params interface{}
+		switch ctx.args[0] {
+		case "get":
+			params, err = command.get(context.Background(), api)
+
+		case "set":
+			if len(ctx.args) < 2 {
+				return errors.New(usage)
+			}
+			var patch map[string]interface{}
+			patch, err = parseFields(ctx.args[1:])
+			if err != nil {
+				return err
+			}
+			params, err = command.set(context.Background(), api, patch)
+
+		default:
+			return errors.New("unknown " + command.name + " command: " + ctx.args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		if ctx.opts.output == outputJSON {
+			return printJSON(os.Stdout, params)
+		}
+
+		rows, err := flattenFields(params)
+		if err != nil {
+			return err
+		}
+		return printTable(os.Stdout, append([][]string{{"FIELD", "VALUE"}}, rows...))
+	}
+}
+
+// parseFields converts field=value arguments to a patch of JSON object
+// Dots in the field select nested objects, numbers and booleans are sent as JSON values
+func parseFields(args []string) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.New("parameter must be field=value: " + arg)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(parts[1]), &value); err != nil {
+			return nil, errors.New("invalid value of " + parts[0] + ": " + parts[1])
+		}
+
+		path := strings.Split(parts[0], ".")
+		object := res
+		for _, name := range path[:len(path)-1] {
+			nested, ok := object[name].(map[string]interface{})
+			if !ok {
+				nested = make(map[string]interface{})
+				object[name] = nested
+			}
+			object = nested
+		}
+		object[path[len(path)-1]] = value
+	}
+	return res, nil
+}
+
+// flattenFields lists values of JSON representation in order of fields, nested fields are joined by dots
+func flattenFields(value interface{}) ([][]string, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var rows [][]string
+	var walk func(path string) error
+	walk = func(path string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'):
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				field := key.(string)
+				if path != "" {
+					field = path + "." + field
+				}
+				if err := walk(field); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(path + "." + strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+
+		case nil:
+			rows = append(rows, []string{path, "null"})
+		default:
+			rows = append(rows, []string{path, fmt.Sprint(token)})
+		}
+		return nil
+	}
+
+	return rows, walk("")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Profile stores connection settings of a single Robot Server
+type Profile struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+
+	// CA pins certificate of the server, Cert and Key are presented to server with mutual TLS
+	CA   string `json:"ca,omitempty"`
+	Cert string `json:"cert,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+// Config is a content of the profiles file
+type Config struct {
+	Current  string              `json:"current"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// defaultServer is used when no profile is configured
+const defaultServer = "http://localhost:8080"
+
+// configPath returns location of the profiles file
+// ROBOTCTL_CONFIG overrides default ~/.config/robotctl.json
+func configPath() string {
+	if path := os.Getenv("ROBOTCTL_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "robotctl.json"
+	}
+	return filepath.Join(home, ".config", "robotctl.json")
+}
+
+// loadConfig reads profiles file, missing file means empty config
+func loadConfig(path string) (*Config, error) {
+	res := &Config{Profiles: make(map[string]*Profile)}
+
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, errors.New(path + ": " + err.Error())
+	}
+	if res.Profiles == nil {
+		res.Profiles = make(map[string]*Profile)
+	}
+	return res, nil
+}
+
+// save writes profiles file, it is readable only by owner because of tokens
+func (config *Config) save(path string) error {
+	body, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(body, '\n'), 0600)
+}
+
+// names returns sorted names of profiles
+func (config *Config) names() []string {
+	var res []string
+	for name := range config.Profiles {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// resolve selects profile by name, empty name selects current profile
+func (config *Config) resolve(name string) (*Profile, error) {
+	if name == "" {
+		name = config.Current
+	}
+	if name == "" {
+		return &Profile{Server: defaultServer}, nil
+	}
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return nil, errors.New("unknown profile: " + name)
+	}
+	res := *profile
+	return &res, nil
+}
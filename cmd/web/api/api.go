@@ -1,9 +1,18 @@
 package api
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
 
 	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
+	"github.com/RadiumByte/Robot-Server/cmd/web/metrics"
 	"github.com/buaazp/fasthttprouter"
 	"github.com/valyala/fasthttp"
 )
@@ -11,29 +20,247 @@ import (
 // WebServer is providing foreign access to the Robot Server
 type WebServer struct {
 	application app.RobotServer
+	logger      *logger.Logger
+	log         *logger.Entry
+	audit       *logger.Entry
+
+	// tokens authenticates clients, nil disables authentication
+	tokens *TokenStore
+
+	// TLS enables HTTPS, nil means plain HTTP
+	TLS *tls.Config
+
+	// spec is OpenAPI document of the routes
+	spec []byte
+
+	// closing is set when server stops accepting commands
+	closing int32
+	// done is closed on shutdown, so long-living streams can finish
+	done chan struct{}
+}
+
+// isClosing checks if server is shutting down
+func (server *WebServer) isClosing() bool {
+	return atomic.LoadInt32(&server.closing) == 1
 }
 
 // ProcessCommand pushes new Command to Application for processing
 func (server *WebServer) PushCommand(ctx *fasthttp.RequestCtx) {
+	if server.isClosing() {
+		ctx.Error("Server is shutting down", fasthttp.StatusServiceUnavailable)
+		return
+	}
+
 	commandStr := ctx.UserValue("command").(string)
-	server.application.ProcessCommand(commandStr)
+	principal := principalOf(ctx)
+	if err := server.application.ProcessCommand(commandStr); err != nil {
+		server.audit.Info("Command rejected", "principal", principal.Name, "command", commandStr,
+			"remote", ctx.RemoteIP().String(), "err", err)
+		ctx.Error(err.Error(), fasthttp.StatusConflict)
+		return
+	}
+	server.audit.Info("Command accepted", "principal", principal.Name, "command", commandStr,
+		"remote", ctx.RemoteIP().String())
+}
+
+// GetStatus returns current state of Application as JSON
+func (server *WebServer) GetStatus(ctx *fasthttp.RequestCtx) {
+	body, err := json.Marshal(server.application.Status())
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+}
+
+// GetMetrics returns all metrics in Prometheus text format
+func (server *WebServer) GetMetrics(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("text/plain; version=0.0.4")
+	metrics.Default.Write(ctx)
+}
+
+// StreamVideo sends annotated autopilot view as MJPEG stream
+func (server *WebServer) StreamVideo(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("multipart/x-mixed-replace; boundary=frame")
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		var lastSeq uint64
+		for {
+			select {
+			case <-server.done:
+				return
+			default:
+			}
+
+			frame, seq := server.application.LatestFrame()
+			if seq == lastSeq || len(frame) == 0 {
+				time.Sleep(20 * time.Millisecond)
+				continue
+			}
+			lastSeq = seq
+
+			fmt.Fprintf(w, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(frame))
+			w.Write(frame)
+			w.WriteString("\r\n")
+			if err := w.Flush(); err != nil {
+				// Client disconnected
+				return
+			}
+		}
+	})
+}
+
+// GetPreflight runs self-test and returns its report
+// Failed self-test is returned with 503 status
+func (server *WebServer) GetPreflight(ctx *fasthttp.RequestCtx) {
+	report := server.application.RunPreflight()
+	body, err := json.Marshal(report)
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	if !report.Passed {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	}
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+}
+
+// writeHealth returns health report, 503 status is set if healthy is false
+func writeHealth(ctx *fasthttp.RequestCtx, report *app.HealthReport, healthy bool) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	if !healthy {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	}
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+}
+
+// GetHealthz reports if Robot Server is alive, e.g. perception loop is not wedged
+func (server *WebServer) GetHealthz(ctx *fasthttp.RequestCtx) {
+	report := server.application.Health()
+	writeHealth(ctx, report, report.Live)
+}
+
+// GetReadyz reports if Robot Server is able to drive the car
+func (server *WebServer) GetReadyz(ctx *fasthttp.RequestCtx) {
+	report := server.application.Health()
+	writeHealth(ctx, report, report.Ready && !server.isClosing())
+}
+
+// GetLogLevels returns verbosity of every component
+func (server *WebServer) GetLogLevels(ctx *fasthttp.RequestCtx) {
+	body, err := json.Marshal(server.logger.Levels())
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+}
+
+// SetLogLevel changes verbosity of the component at runtime
+// Component "default" changes level of components without explicit verbosity
+func (server *WebServer) SetLogLevel(ctx *fasthttp.RequestCtx) {
+	component := ctx.UserValue("component").(string)
+	level, err := logger.ParseLevel(ctx.UserValue("level").(string))
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if component == "default" {
+		component = ""
+	}
+	server.logger.SetLevel(component, level)
+	server.audit.Info("Log level changed", "principal", principalOf(ctx).Name,
+		"target", ctx.UserValue("component"), "verbosity", level.String())
 }
 
 // Start initializes Web Server, starts application and begins serving
-func (server *WebServer) Start(port string) {
-	server.application.Start()
+// It returns when context is cancelled or server fails
+func (server *WebServer) Start(ctx context.Context, port string) error {
+	server.application.Start(ctx)
+
+	if server.tokens != nil {
+		go server.watchTokens(5 * time.Second)
+	}
 
+	// Dashboard page, probes and API document are public, the page asks for a token itself
 	router := fasthttprouter.New()
-	router.PUT("/:command", server.PushCommand)
+	for _, r := range server.routes() {
+		handler := r.Handler
+		if r.Role != "" {
+			handler = server.requireRole(r.Role, handler)
+		}
+		router.Handle(r.Method, r.Path, handler)
+	}
+
+	httpServer := &fasthttp.Server{Handler: router.Handler}
+
+	listener, err := net.Listen("tcp4", port)
+	if err != nil {
+		server.stopAccepting()
+		return err
+	}
+	if server.TLS != nil {
+		listener = tls.NewListener(listener, server.TLS)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		server.log.Info("Server is starting", "port", port, "tls", server.TLS != nil,
+			"mtls", server.TLS != nil && server.TLS.ClientAuth == tls.RequireAndVerifyClientCert)
+		errs <- httpServer.Serve(listener)
+	}()
 
-	fmt.Println("Server is starting on port" + port)
-	fasthttp.ListenAndServe(port, router.Handler)
+	select {
+	case err := <-errs:
+		server.stopAccepting()
+		return err
+	case <-ctx.Done():
+	}
+
+	server.log.Info("Server is shutting down")
+	server.stopAccepting()
+	return httpServer.Shutdown()
+}
+
+// stopAccepting rejects new commands and finishes streams
+func (server *WebServer) stopAccepting() {
+	if atomic.CompareAndSwapInt32(&server.closing, 0, 1) {
+		close(server.done)
+	}
 }
 
 // NewWebServer constructs Web Server
-func NewWebServer(application app.RobotServer) (*WebServer, error) {
+// Nil tokens disable authentication, every client is an operator then
+func NewWebServer(application app.RobotServer, log *logger.Logger, tokens *TokenStore) (*WebServer, error) {
 	res := &WebServer{}
 	res.application = application
+	res.logger = log
+	res.log = log.Component("api")
+	res.audit = log.Component("audit")
+	res.done = make(chan struct{})
+
+	spec, err := buildSpec(res.routes())
+	if err != nil {
+		return nil, err
+	}
+	res.spec = spec
+
+	res.tokens = tokens
+	if tokens == nil {
+		res.log.Warn("Token file is not set, authentication is disabled")
+	}
 
 	return res, nil
 }
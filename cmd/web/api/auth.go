@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Roles of API clients
+// Operator is allowed everything, which viewer is allowed
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+)
+
+// roleRanks orders roles by their privileges
+var roleRanks = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+}
+
+// Principal is an identity of the API client
+type Principal struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	Role  string `json:"role"`
+}
+
+// Anonymous is a principal of every client, when authentication is disabled
+var Anonymous = &Principal{Name: "anonymous", Role: RoleOperator}
+
+// Allows checks if principal has at least the role
+func (principal *Principal) Allows(role string) bool {
+	return roleRanks[principal.Role] >= roleRanks[role]
+}
+
+// TokenStore keeps tokens of API clients and reloads them, when file changes
+// File is a JSON array of principals:
+// [{"name": "alice", "token": "secret", "role": "operator"}]
+type TokenStore struct {
+	Path string
+
+	mutex      sync.RWMutex
+	principals map[string]*Principal
+	modTime    time.Time
+}
+
+// loadTokens reads and validates token file
+func loadTokens(path string) (map[string]*Principal, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Principal
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	res := make(map[string]*Principal, len(list))
+	for i, principal := range list {
+		if principal.Name == "" || principal.Token == "" {
+			return nil, fmt.Errorf("%s: entry %d has no name or token", path, i)
+		}
+		if _, ok := roleRanks[principal.Role]; !ok {
+			return nil, fmt.Errorf("%s: %s has unknown role %q", path, principal.Name, principal.Role)
+		}
+		if _, ok := res[principal.Token]; ok {
+			return nil, fmt.Errorf("%s: token of %s is not unique", path, principal.Name)
+		}
+		res[principal.Token] = principal
+	}
+	return res, nil
+}
+
+// Reload reads token file again if it was modified
+// Broken file is reported and previous tokens stay active
+func (store *TokenStore) Reload() (bool, error) {
+	info, err := os.Stat(store.Path)
+	if err != nil {
+		return false, err
+	}
+
+	store.mutex.RLock()
+	unchanged := info.ModTime().Equal(store.modTime)
+	store.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	principals, err := loadTokens(store.Path)
+	if err != nil {
+		return false, err
+	}
+
+	store.mutex.Lock()
+	store.principals = principals
+	store.modTime = info.ModTime()
+	store.mutex.Unlock()
+	return true, nil
+}
+
+// Lookup finds principal by the token
+func (store *TokenStore) Lookup(token string) (*Principal, bool) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	principal, ok := store.principals[token]
+	return principal, ok
+}
+
+// NewTokenStore constructs TokenStore and loads tokens from the file
+func NewTokenStore(path string) (*TokenStore, error) {
+	res := &TokenStore{}
+	res.Path = path
+
+	if _, err := res.Reload(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// errNoToken is returned when client did not present a token
+var errNoToken = errors.New("authentication token is required")
+
+// requestToken extracts token from Authorization header or "token" query argument
+// Query argument is needed for <img> and WebSocket clients, which can't set headers
+func requestToken(ctx *fasthttp.RequestCtx) string {
+	header := string(ctx.Request.Header.Peek("Authorization"))
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	return string(ctx.QueryArgs().Peek("token"))
+}
+
+// authenticate finds principal of the request
+func (server *WebServer) authenticate(ctx *fasthttp.RequestCtx) (*Principal, error) {
+	if server.tokens == nil {
+		return Anonymous, nil
+	}
+
+	token := requestToken(ctx)
+	if token == "" {
+		return nil, errNoToken
+	}
+
+	principal, ok := server.tokens.Lookup(token)
+	if !ok {
+		return nil, errors.New("authentication token is invalid")
+	}
+	return principal, nil
+}
+
+// principalKey is a key of the principal in user values of the request
+const principalKey = "principal"
+
+// principalOf returns principal, stored by requireRole
+func principalOf(ctx *fasthttp.RequestCtx) *Principal {
+	if principal, ok := ctx.UserValue(principalKey).(*Principal); ok {
+		return principal
+	}
+	return Anonymous
+}
+
+// requireRole wraps handler, so it is called only for clients with the role
+func (server *WebServer) requireRole(role string, handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		principal, err := server.authenticate(ctx)
+		if err != nil {
+			authFailures.Inc("unauthenticated")
+			ctx.Response.Header.Set("WWW-Authenticate", `Bearer realm="robot-server"`)
+			ctx.Error(err.Error(), fasthttp.StatusUnauthorized)
+			return
+		}
+
+		if !principal.Allows(role) {
+			authFailures.Inc("forbidden")
+			server.audit.Warn("Access denied", "principal", principal.Name, "role", principal.Role,
+				"required", role, "method", string(ctx.Method()), "path", string(ctx.Path()),
+				"remote", ctx.RemoteIP().String())
+			ctx.Error("role "+role+" is required", fasthttp.StatusForbidden)
+			return
+		}
+
+		ctx.SetUserValue(principalKey, principal)
+		handler(ctx)
+	}
+}
+
+// watchTokens reloads token file periodically until server stops
+func (server *WebServer) watchTokens(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-server.done:
+			return
+		case <-ticker.C:
+			reloaded, err := server.tokens.Reload()
+			if err != nil {
+				server.log.Error("Token file reload failure, previous tokens are kept", "err", err)
+			} else if reloaded {
+				server.log.Info("Token file reloaded", "path", server.tokens.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/valyala/fasthttp"
+)
+
+// GetCamera returns capture properties reported by the camera and its saved profile
+func (server *WebServer) GetCamera(ctx *fasthttp.RequestCtx) {
+	state, err := server.application.CameraState()
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+	writeJSON(ctx, fasthttp.StatusOK, state)
+}
+
+// SetCamera changes capture properties of the camera used by autopilot
+// Values rejected by the camera are restored, response lists what the camera reported back
+func (server *WebServer) SetCamera(ctx *fasthttp.RequestCtx) {
+	var settings app.CameraSettings
+	if err := json.Unmarshal(ctx.PostBody(), &settings); err != nil {
+		ctx.Error("invalid camera request: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if err := settings.Validate(); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	update, err := server.application.SetCamera(settings)
+	if err == app.ErrCameraClosed {
+		ctx.Error(err.Error(), fasthttp.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	server.audit.Info("Camera properties changed", "principal", principalOf(ctx).Name,
+		"properties", string(ctx.PostBody()), "applied", update.Applied, "remote", ctx.RemoteIP().String())
+
+	status := fasthttp.StatusOK
+	if !update.Applied {
+		status = fasthttp.StatusUnprocessableEntity
+	}
+	writeJSON(ctx, status, update)
+}
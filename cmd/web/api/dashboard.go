@@ -0,0 +1,334 @@
+package api
+
+import (
+	"github.com/valyala/fasthttp"
+)
+
+// GetDashboard returns operator dashboard, all assets are bundled into the page
+func (server *WebServer) GetDashboard(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("text/html; charset=utf-8")
+	ctx.SetBodyString(dashboardPage)
+}
+
+// dashboardPage is a single page operator UI
+// It uses only endpoints of the Web Server: /video, /status, /api/v1/recording and PUT /:command
+// Token is kept in browser local storage and sent with every request
+const dashboardPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Robot Server</title>
+<style>
+body { font-family: sans-serif; background: #1e1e1e; color: #ddd; margin: 0; }
+header { padding: 8px 16px; background: #333; display: flex; justify-content: space-between; }
+main { display: grid; grid-template-columns: 660px 1fr; gap: 16px; padding: 16px; }
+section { background: #2a2a2a; padding: 12px; border-radius: 4px; margin-bottom: 16px; }
+h2 { font-size: 14px; margin: 0 0 8px 0; color: #aaa; text-transform: uppercase; }
+button { background: #444; color: #ddd; border: 1px solid #666; padding: 8px 14px; margin: 2px; cursor: pointer; }
+button:hover { background: #555; }
+button.danger { background: #822; }
+button.recording { background: #a22; }
+img.video { width: 640px; height: 480px; background: #000; }
+#joystick { width: 200px; height: 200px; background: #333; border-radius: 50%; position: relative; touch-action: none; }
+#knob { width: 50px; height: 50px; background: #888; border-radius: 50%; position: absolute; left: 75px; top: 75px; }
+canvas { background: #222; width: 100%; height: 120px; }
+#log { height: 180px; overflow-y: auto; font-family: monospace; font-size: 12px; }
+.error { color: #f66; }
+</style>
+</head>
+<body>
+<header><span>Robot Server</span><span id="mode">-</span>
+<span><input id="token" type="password" placeholder="API token"> <button id="login">Use token</button></span></header>
+<main>
+<div>
+<section><h2>Autopilot view</h2><img id="video" class="video" alt="video"></section>
+<section>
+<h2>Control</h2>
+<button class="danger" data-command="halt">Halt</button>
+<button data-command="go">Go</button>
+<button data-command="manual">Manual</button>
+<button data-command="auto">Auto</button>
+<button data-command="debugon">Debug overlay</button>
+<button data-command="debugoff">Target overlay</button>
+<button id="record">Record</button>
+<select id="sign">
+<option value="stopsign">Stop sign</option>
+<option value="circlesign">Circle sign</option>
+<option value="yieldsign">Yield sign</option>
+</select>
+</section>
+</div>
+<div>
+<section>
+<h2>Manual driving</h2>
+<div id="joystick"><div id="knob"></div></div>
+<p>Keyboard: W/S throttle, A/D steering, Space stop. Gamepad: left stick.</p>
+</section>
+<section><h2>Throttle</h2><canvas id="throttle" width="600" height="120"></canvas></section>
+<section><h2>Steering</h2><canvas id="steering" width="600" height="120"></canvas></section>
+<section><h2>FPS</h2><canvas id="fps" width="600" height="120"></canvas></section>
+<section><h2>Command log</h2><div id="log"></div></section>
+</div>
+</main>
+<script>
+"use strict";
+
+function log(text, isError) {
+	var line = document.createElement("div");
+	line.textContent = new Date().toLocaleTimeString() + " " + text;
+	if (isError) {
+		line.className = "error";
+	}
+	var box = document.getElementById("log");
+	box.insertBefore(line, box.firstChild);
+	while (box.childNodes.length > 200) {
+		box.removeChild(box.lastChild);
+	}
+}
+
+var token = localStorage.getItem("token") || "";
+
+function authHeaders() {
+	return token ? {"Authorization": "Bearer " + token} : {};
+}
+
+function connectVideo() {
+	document.getElementById("video").src = "/video" + (token ? "?token=" + encodeURIComponent(token) : "");
+}
+
+document.getElementById("token").value = token;
+document.getElementById("login").addEventListener("click", function () {
+	token = document.getElementById("token").value;
+	localStorage.setItem("token", token);
+	connectVideo();
+});
+connectVideo();
+
+function send(command, quiet) {
+	return fetch("/" + command, {method: "PUT", headers: authHeaders()}).then(function (response) {
+		if (!response.ok) {
+			return response.text().then(function (text) {
+				log(command + ": " + text, true);
+			});
+		}
+		if (!quiet) {
+			log(command + ": ok");
+		}
+	}).catch(function (err) {
+		log(command + ": " + err, true);
+	});
+}
+
+document.querySelectorAll("button[data-command]").forEach(function (button) {
+	button.addEventListener("click", function () {
+		send(button.getAttribute("data-command"));
+	});
+});
+document.getElementById("sign").addEventListener("change", function (event) {
+	send(event.target.value);
+});
+
+// Recording of the autopilot view is toggled by a single button
+var recording = false;
+
+function showRecording(status) {
+	recording = status.recording;
+	var button = document.getElementById("record");
+	button.textContent = recording ? "Stop recording (" + status.frames + ")" : "Record";
+	button.className = recording ? "recording" : "";
+}
+
+document.getElementById("record").addEventListener("click", function () {
+	var path = recording ? "/api/v1/recording/stop" : "/api/v1/recording/start";
+	fetch(path, {method: "POST", headers: authHeaders()}).then(function (response) {
+		if (!response.ok) {
+			return response.text().then(function (text) {
+				log("recording: " + text, true);
+			});
+		}
+		return response.json().then(function (status) {
+			showRecording(status);
+			log(status.recording ? "recording: started" : "recording: stopped, " + status.files.length + " files");
+		});
+	}).catch(function (err) {
+		log("recording: " + err, true);
+	});
+});
+
+setInterval(function () {
+	fetch("/api/v1/recording", {headers: authHeaders()}).then(function (response) {
+		if (response.ok) {
+			return response.json().then(showRecording);
+		}
+	}).catch(function () {});
+}, 2000);
+
+// Manual driving: throttle is -100..100, steering is 0..100
+var drive = {throttle: 0, steering: 50, sentThrottle: 0, sentSteering: 50};
+
+function sendDrive() {
+	if (drive.steering !== drive.sentSteering) {
+		drive.sentSteering = drive.steering;
+		send("S" + drive.steering, true);
+	}
+	if (drive.throttle !== drive.sentThrottle) {
+		drive.sentThrottle = drive.throttle;
+		if (drive.throttle >= 0) {
+			send("F" + drive.throttle, true);
+		} else {
+			send("B" + (-drive.throttle), true);
+		}
+	}
+}
+
+function setDrive(x, y) {
+	// x and y are -1..1, up is forward
+	drive.steering = Math.round(50 + x * 50);
+	drive.throttle = Math.round(-y * 100);
+	var knob = document.getElementById("knob");
+	knob.style.left = (75 + x * 75) + "px";
+	knob.style.top = (75 + y * 75) + "px";
+}
+
+var joystick = document.getElementById("joystick");
+var dragging = false;
+function onPointer(event) {
+	if (!dragging) {
+		return;
+	}
+	var rect = joystick.getBoundingClientRect();
+	var x = (event.clientX - rect.left - rect.width / 2) / (rect.width / 2);
+	var y = (event.clientY - rect.top - rect.height / 2) / (rect.height / 2);
+	setDrive(Math.max(-1, Math.min(1, x)), Math.max(-1, Math.min(1, y)));
+}
+joystick.addEventListener("pointerdown", function (event) {
+	dragging = true;
+	joystick.setPointerCapture(event.pointerId);
+	onPointer(event);
+});
+joystick.addEventListener("pointermove", onPointer);
+joystick.addEventListener("pointerup", function () {
+	dragging = false;
+	setDrive(0, 0);
+});
+
+// Typing into form fields and shortcuts with modifiers must not drive the car
+function isDriveKey(event) {
+	var target = event.target;
+	if (target.isContentEditable || ["INPUT", "SELECT", "TEXTAREA"].indexOf(target.tagName) >= 0) {
+		return false;
+	}
+	return !(event.ctrlKey || event.altKey || event.metaKey);
+}
+var keys = {};
+document.addEventListener("keydown", function (event) {
+	if (!isDriveKey(event)) {
+		return;
+	}
+	keys[event.key.toLowerCase()] = true;
+	updateKeys();
+});
+document.addEventListener("keyup", function (event) {
+	keys[event.key.toLowerCase()] = false;
+	updateKeys();
+});
+function updateKeys() {
+	if (keys[" "]) {
+		setDrive(0, 0);
+		return;
+	}
+	var x = (keys["d"] || keys["arrowright"] ? 1 : 0) - (keys["a"] || keys["arrowleft"] ? 1 : 0);
+	var y = (keys["s"] || keys["arrowdown"] ? 1 : 0) - (keys["w"] || keys["arrowup"] ? 1 : 0);
+	setDrive(x * 0.6, y * 0.6);
+}
+
+function pollGamepad() {
+	var pads = navigator.getGamepads ? navigator.getGamepads() : [];
+	for (var i = 0; i < pads.length; i++) {
+		var pad = pads[i];
+		if (pad && pad.axes.length >= 2) {
+			var x = Math.abs(pad.axes[0]) > 0.1 ? pad.axes[0] : 0;
+			var y = Math.abs(pad.axes[1]) > 0.1 ? pad.axes[1] : 0;
+			if (x !== 0 || y !== 0 || gamepadActive) {
+				setDrive(x, y);
+			}
+			gamepadActive = x !== 0 || y !== 0;
+			break;
+		}
+	}
+}
+var gamepadActive = false;
+
+setInterval(function () {
+	pollGamepad();
+	sendDrive();
+}, 100);
+
+// Telemetry charts
+function Chart(id, min, max) {
+	this.canvas = document.getElementById(id);
+	this.min = min;
+	this.max = max;
+	this.values = [];
+}
+Chart.prototype.push = function (value) {
+	this.values.push(value);
+	if (this.values.length > 120) {
+		this.values.shift();
+	}
+	var ctx = this.canvas.getContext("2d");
+	var w = this.canvas.width;
+	var h = this.canvas.height;
+	ctx.clearRect(0, 0, w, h);
+	ctx.strokeStyle = "#555";
+	ctx.beginPath();
+	var zero = h - (0 - this.min) / (this.max - this.min) * h;
+	ctx.moveTo(0, zero);
+	ctx.lineTo(w, zero);
+	ctx.stroke();
+	ctx.strokeStyle = "#4c4";
+	ctx.beginPath();
+	for (var i = 0; i < this.values.length; i++) {
+		var x = i * w / 120;
+		var y = h - (this.values[i] - this.min) / (this.max - this.min) * h;
+		if (i === 0) {
+			ctx.moveTo(x, y);
+		} else {
+			ctx.lineTo(x, y);
+		}
+	}
+	ctx.stroke();
+	ctx.fillStyle = "#ddd";
+	ctx.fillText(String(Math.round(value * 10) / 10), 4, 12);
+};
+
+var charts = {
+	throttle: new Chart("throttle", -100, 100),
+	steering: new Chart("steering", 0, 100),
+	fps: new Chart("fps", 0, 60)
+};
+
+setInterval(function () {
+	fetch("/status", {headers: authHeaders()}).then(function (response) {
+		if (!response.ok) {
+			throw new Error(response.statusText);
+		}
+		return response.json();
+	}).then(function (status) {
+		var mode = status.manual ? "manual" : "auto";
+		if (status.blocked) {
+			mode += ", blocked";
+		}
+		mode += ", " + status.acquisition.state;
+		document.getElementById("mode").textContent = mode;
+		charts.throttle.push(status.telemetry.throttle);
+		charts.steering.push(status.telemetry.steering);
+		charts.fps.push(status.telemetry.fps);
+	}).catch(function (err) {
+		document.getElementById("mode").textContent = "offline";
+	});
+}, 500);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/valyala/fasthttp"
+)
+
+// GetMasks returns include and exclude zones of the frame
+func (server *WebServer) GetMasks(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, server.application.MaskZones())
+}
+
+// SetMasks replaces include and exclude zones, empty body fields remove zones
+func (server *WebServer) SetMasks(ctx *fasthttp.RequestCtx) {
+	var params app.MaskParams
+	if err := json.Unmarshal(ctx.PostBody(), &params); err != nil {
+		ctx.Error("invalid masks request: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if err := server.application.SetMasks(params); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	server.audit.Info("Masks changed", "principal", principalOf(ctx).Name,
+		"include", len(params.Include), "exclude", len(params.Exclude), "remote", ctx.RemoteIP().String())
+	writeJSON(ctx, fasthttp.StatusOK, server.application.MaskZones())
+}
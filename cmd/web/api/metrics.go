@@ -0,0 +1,11 @@
+package api
+
+import (
+	"github.com/RadiumByte/Robot-Server/cmd/web/metrics"
+)
+
+// Web Server metrics
+var (
+	authFailures = metrics.NewCounter("robot_api_auth_failures_total",
+		"Rejected API requests per reason.", "reason")
+)
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// apiVersion is a version of the HTTP API in the OpenAPI document
+const apiVersion = "1.0.0"
+
+// GetOpenAPI returns OpenAPI 3 document of the Web Server
+func (server *WebServer) GetOpenAPI(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("application/json")
+	ctx.SetBody(server.spec)
+}
+
+// schemaBuilder converts Go types into JSON schemas
+// Named structs are stored once in components and referenced
+type schemaBuilder struct {
+	components map[string]interface{}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// schema returns JSON schema of the type
+func (builder *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return builder.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": builder.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": builder.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return builder.object(t)
+		}
+		if _, ok := builder.components[t.Name()]; !ok {
+			// Placeholder breaks recursion of self-referencing types
+			builder.components[t.Name()] = nil
+			builder.components[t.Name()] = builder.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	// Interfaces accept any value
+	return map[string]interface{}{}
+}
+
+// object returns schema of struct fields, named according to json tags
+func (builder *schemaBuilder) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := field.Name
+		options := strings.Split(tag, ",")
+		if options[0] != "" {
+			name = options[0]
+		}
+
+		properties[name] = builder.schema(field.Type)
+		if !strings.Contains(tag, ",omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	res := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		res["required"] = required
+	}
+	return res
+}
+
+// openAPIPath converts router path to OpenAPI template, e.g. /:command to /{command}
+func openAPIPath(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operation returns OpenAPI operation of the route
+func (builder *schemaBuilder) operation(r route) map[string]interface{} {
+	path, pathParams := openAPIPath(r.Path)
+	isPathParam := make(map[string]bool)
+
+	var params []interface{}
+	for _, name := range pathParams {
+		isPathParam[name] = true
+		params = append(params, map[string]interface{}{
+			"name": name, "in": "path", "required": true,
+			"description": r.Params[name], "schema": map[string]interface{}{"type": "string"},
+		})
+	}
+
+	var queryParams []string
+	for name := range r.Params {
+		if !isPathParam[name] {
+			queryParams = append(queryParams, name)
+		}
+	}
+	sort.Strings(queryParams)
+	for _, name := range queryParams {
+		params = append(params, map[string]interface{}{
+			"name": name, "in": "query", "description": r.Params[name],
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = fasthttp.StatusOK
+	}
+	success := map[string]interface{}{"description": fasthttp.StatusMessage(status)}
+	if r.Body != nil {
+		success["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": builder.schema(reflect.TypeOf(r.Body))},
+		}
+	} else if r.ContentType != "" {
+		success["content"] = map[string]interface{}{
+			r.ContentType: map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+		}
+	}
+
+	responses := map[string]interface{}{strconv.Itoa(status): success}
+	for code, description := range r.Errors {
+		response := map[string]interface{}{"description": description}
+		if r.Body != nil && strings.Contains(description, "body is the report") {
+			response["content"] = success["content"]
+		}
+		responses[strconv.Itoa(code)] = response
+	}
+
+	res := map[string]interface{}{
+		"summary":     r.Summary,
+		"operationId": operationID(r.Method, path),
+		"responses":   responses,
+	}
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+	if r.Request != nil {
+		request := reflect.TypeOf(r.Request)
+		// Body is optional when all of its fields are optional
+		required := true
+		if request.Kind() == reflect.Struct {
+			_, required = builder.object(request)["required"]
+		}
+		res["requestBody"] = map[string]interface{}{
+			"required": required,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": builder.schema(request)},
+			},
+		}
+	}
+
+	if r.Role == "" {
+		res["security"] = []interface{}{}
+	} else {
+		res["x-required-role"] = r.Role
+		responses["401"] = map[string]interface{}{"description": "Token is missing or invalid"}
+		responses["403"] = map[string]interface{}{"description": "Role " + r.Role + " is required"}
+	}
+	return res
+}
+
+// operationID builds identifier from method and path, e.g. getLogComponentLevel
+func operationID(method string, path string) string {
+	res := strings.ToLower(method)
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		res += strings.ToUpper(word[:1]) + word[1:]
+	}
+	if res == strings.ToLower(method) {
+		res += "Root"
+	}
+	return res
+}
+
+// buildSpec returns OpenAPI document of the routes
+func buildSpec(routes []route) ([]byte, error) {
+	builder := &schemaBuilder{components: make(map[string]interface{})}
+
+	paths := make(map[string]map[string]interface{})
+	for _, r := range routes {
+		path, _ := openAPIPath(r.Path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		paths[path][strings.ToLower(r.Method)] = builder.operation(r)
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Robot Server API",
+			"version":     apiVersion,
+			"description": "Control and monitoring API of the Robot Server",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": builder.components,
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"},
+				"query":  map[string]interface{}{"type": "apiKey", "in": "query", "name": "token"},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearer": []string{}},
+			map[string]interface{}{"query": []string{}},
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
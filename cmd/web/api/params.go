@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/valyala/fasthttp"
+)
+
+// tunable binds params of an autopilot stage to GET and POST handlers
+type tunable struct {
+	// name is used in errors and audit records
+	name string
+
+	// get returns pointer to a copy of current params, request body is decoded over it
+	get func() interface{}
+
+	// set validates and applies params, which get returned
+	set func(params interface{}) error
+}
+
+// getParams returns handler, which responds with current params
+func (server *WebServer) getParams(stage tunable) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		writeJSON(ctx, fasthttp.StatusOK, stage.get())
+	}
+}
+
+// setParams returns handler, which changes params and responds with all of them
+// Body is merged into current params, so only changed fields have to be sent
+func (server *WebServer) setParams(stage tunable) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		params := stage.get()
+		if err := json.Unmarshal(ctx.PostBody(), params); err != nil {
+			ctx.Error("invalid "+stage.name+" request: "+err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+		if err := stage.set(params); err != nil {
+			ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+
+		server.audit.Info("Params changed", "stage", stage.name, "principal", principalOf(ctx).Name,
+			"changes", string(ctx.PostBody()), "remote", ctx.RemoteIP().String())
+		writeJSON(ctx, fasthttp.StatusOK, params)
+	}
+}
+
+// Stages of the autopilot with tunable params
+
+func (server *WebServer) preprocessParams() tunable {
+	return tunable{
+		name: "preprocess",
+		get: func() interface{} {
+			params := server.application.Preprocessing()
+			return &params
+		},
+		set: func(params interface{}) error {
+			return server.application.SetPreprocess(*params.(*app.PreprocessParams))
+		},
+	}
+}
+
+func (server *WebServer) qualityParams() tunable {
+	return tunable{
+		name: "quality",
+		get: func() interface{} {
+			params := server.application.QualityThresholds()
+			return &params
+		},
+		set: func(params interface{}) error {
+			return server.application.SetQuality(*params.(*app.QualityParams))
+		},
+	}
+}
+
+func (server *WebServer) acquisitionParams() tunable {
+	return tunable{
+		name: "acquisition",
+		get: func() interface{} {
+			params := server.application.AcquisitionSettings()
+			return &params
+		},
+		set: func(params interface{}) error {
+			return server.application.SetAcquisition(*params.(*app.AcquisitionParams))
+		},
+	}
+}
+
+func (server *WebServer) searchParams() tunable {
+	return tunable{
+		name: "search",
+		get: func() interface{} {
+			params := server.application.SearchMode()
+			return &params
+		},
+		set: func(params interface{}) error {
+			return server.application.SetSearch(*params.(*app.SearchParams))
+		},
+	}
+}
+
+func (server *WebServer) shapeParams() tunable {
+	return tunable{
+		name: "shape",
+		get: func() interface{} {
+			params := server.application.ShapeThresholds()
+			return &params
+		},
+		set: func(params interface{}) error {
+			return server.application.SetShape(*params.(*app.ShapeThresholds))
+		},
+	}
+}
+
+func (server *WebServer) groupingParams() tunable {
+	return tunable{
+		name: "grouping",
+		get: func() interface{} {
+			params := server.application.GroupingSettings()
+			return &params
+		},
+		set: func(params interface{}) error {
+			return server.application.SetGrouping(*params.(*app.GroupingParams))
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/valyala/fasthttp"
+)
+
+// writeJSON responds with value encoded as JSON
+func writeJSON(ctx *fasthttp.RequestCtx, status int, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+	ctx.SetStatusCode(status)
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+}
+
+// GetRecording returns state of the current or the last video recording
+func (server *WebServer) GetRecording(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, server.application.Recording())
+}
+
+// StartRecording starts video recording of the annotated autopilot view, body is optional
+func (server *WebServer) StartRecording(ctx *fasthttp.RequestCtx) {
+	var params app.RecordingParams
+	if body := ctx.PostBody(); len(body) > 0 {
+		if err := json.Unmarshal(body, &params); err != nil {
+			ctx.Error("invalid recording request: "+err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+	}
+	if err := params.Validate(); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	status, err := server.application.StartRecording(params)
+	if err == app.ErrRecordingRunning {
+		ctx.Error(err.Error(), fasthttp.StatusConflict)
+		return
+	}
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	server.audit.Info("Recording started", "principal", principalOf(ctx).Name, "remote", ctx.RemoteIP().String())
+	writeJSON(ctx, fasthttp.StatusOK, status)
+}
+
+// StopRecording stops video recording, it does nothing when recording is not running
+func (server *WebServer) StopRecording(ctx *fasthttp.RequestCtx) {
+	status := server.application.StopRecording()
+	server.audit.Info("Recording stopped", "principal", principalOf(ctx).Name, "remote", ctx.RemoteIP().String())
+	writeJSON(ctx, fasthttp.StatusOK, status)
+}
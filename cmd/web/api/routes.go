@@ -0,0 +1,248 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/valyala/fasthttp"
+)
+
+// route describes a single endpoint of the Web Server
+// Router and OpenAPI document are both built from routes, so they can't diverge
+type route struct {
+	Method string
+	// Path uses router syntax, e.g. /log/:component/:level
+	Path    string
+	Summary string
+	// Role is required from a client, empty role means public endpoint
+	Role    string
+	Handler fasthttp.RequestHandler
+
+	// Status and ContentType of the successful response
+	Status      int
+	ContentType string
+	// Body is an example value, which type describes JSON response
+	Body interface{}
+	// Request is an example value, which type describes JSON request body
+	Request interface{}
+	// Errors lists additional responses of the endpoint
+	Errors map[int]string
+	// Params describes path and query parameters
+	Params map[string]string
+}
+
+// routes returns all endpoints of the Web Server
+func (server *WebServer) routes() []route {
+	return []route{
+		{
+			Method: "PUT", Path: "/:command", Role: RoleOperator, Handler: server.PushCommand,
+			Summary: "Send a command to the application",
+			Params: map[string]string{"command": "halt, go, manual, auto, stopsign, circlesign, yieldsign, " +
+				"debugon, debugoff or manual driving S0..S100, F0..F100, B0..B100"},
+			Errors: map[int]string{
+				fasthttp.StatusConflict:           "Command was refused",
+				fasthttp.StatusServiceUnavailable: "Server is shutting down",
+			},
+		},
+		{
+			Method: "GET", Path: "/status", Role: RoleViewer, Handler: server.GetStatus,
+			Summary: "Current state of the application", Body: app.Status{},
+		},
+		{
+			Method: "GET", Path: "/video", Role: RoleViewer, Handler: server.StreamVideo,
+			Summary: "Annotated autopilot view as MJPEG stream", ContentType: "multipart/x-mixed-replace; boundary=frame",
+		},
+		{
+			Method: "GET", Path: "/events", Role: RoleViewer, Handler: server.StreamEvents,
+			Summary: "WebSocket stream of application events, every message is an Event",
+			Status:  fasthttp.StatusSwitchingProtocols, Body: app.Event{},
+			Params: map[string]string{"types": "Comma separated event types: " +
+				"frame, command, mode, error, watchdog. All types by default"},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Request is not a WebSocket upgrade"},
+		},
+		{
+			Method: "GET", Path: "/metrics", Role: RoleViewer, Handler: server.GetMetrics,
+			Summary: "Metrics in Prometheus text format", ContentType: "text/plain; version=0.0.4",
+		},
+		{
+			Method: "GET", Path: "/log", Role: RoleViewer, Handler: server.GetLogLevels,
+			Summary: "Log verbosity of every component", Body: map[string]string{},
+		},
+		{
+			Method: "POST", Path: "/log/:component/:level", Role: RoleOperator, Handler: server.SetLogLevel,
+			Summary: "Change log verbosity of the component",
+			Params: map[string]string{
+				"component": "Name of the component, \"default\" changes components without explicit verbosity",
+				"level":     "debug, info, warn or error",
+			},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Unknown level"},
+		},
+		{
+			Method: "GET", Path: "/preflight", Role: RoleOperator, Handler: server.GetPreflight,
+			Summary: "Run self-test", Body: app.PreflightReport{},
+			Errors: map[int]string{fasthttp.StatusServiceUnavailable: "Self-test failed, body is the report"},
+		},
+		{
+			Method: "GET", Path: "/healthz", Handler: server.GetHealthz,
+			Summary: "Liveness probe", Body: app.HealthReport{},
+			Errors: map[int]string{fasthttp.StatusServiceUnavailable: "Server is not alive, body is the report"},
+		},
+		{
+			Method: "GET", Path: "/readyz", Handler: server.GetReadyz,
+			Summary: "Readiness probe", Body: app.HealthReport{},
+			Errors: map[int]string{fasthttp.StatusServiceUnavailable: "Server is not ready, body is the report"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/snapshot", Role: RoleViewer, Handler: server.GetSnapshot,
+			Summary: "Latest frame as JPEG or PNG", ContentType: "image/jpeg",
+			Params: map[string]string{
+				"kind":   "raw camera frame or annotated processed frame, annotated by default",
+				"format": "jpeg or png, jpeg by default",
+				"width":  "Width of resized frame, height keeps aspect ratio if not set",
+				"height": "Height of resized frame, width keeps aspect ratio if not set",
+			},
+			Errors: map[int]string{
+				fasthttp.StatusBadRequest:         "Invalid parameters",
+				fasthttp.StatusServiceUnavailable: "No frame processed yet",
+			},
+		},
+		{
+			Method: "POST", Path: "/api/v1/captures", Role: RoleOperator, Handler: server.PostCapture,
+			Summary: "Save the next frames with their detections to disk",
+			Status:  fasthttp.StatusAccepted, Request: app.CaptureParams{}, Body: app.CaptureStatus{},
+			Errors: map[int]string{
+				fasthttp.StatusBadRequest: "Invalid parameters",
+				fasthttp.StatusConflict:   "Another capture is running",
+			},
+		},
+		{
+			Method: "GET", Path: "/api/v1/captures", Role: RoleViewer, Handler: server.GetCaptures,
+			Summary: "Progress of recent capture jobs", Body: []app.CaptureStatus{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/recording", Role: RoleViewer, Handler: server.GetRecording,
+			Summary: "State of the current or the last video recording", Body: app.RecordingStatus{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/recording/start", Role: RoleOperator, Handler: server.StartRecording,
+			Summary: "Record annotated autopilot view to MJPG/AVI files, body is optional",
+			Request: app.RecordingParams{}, Body: app.RecordingStatus{},
+			Errors: map[int]string{
+				fasthttp.StatusBadRequest: "Invalid parameters",
+				fasthttp.StatusConflict:   "Recording is already running",
+			},
+		},
+		{
+			Method: "POST", Path: "/api/v1/recording/stop", Role: RoleOperator, Handler: server.StopRecording,
+			Summary: "Stop video recording", Body: app.RecordingStatus{},
+		},
+		{
+			Method: "GET", Path: "/api/v1/camera", Role: RoleViewer, Handler: server.GetCamera,
+			Summary: "Capture properties reported by the camera and its saved profile", Body: app.CameraState{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/camera", Role: RoleOperator, Handler: server.SetCamera,
+			Summary: "Change capture properties, values accepted by the camera are saved to its profile. " +
+				"Properties: " + strings.Join(app.CameraPropertyNames(), ", "),
+			Request: app.CameraSettings{}, Body: app.CameraUpdate{},
+			Errors: map[int]string{
+				fasthttp.StatusBadRequest:          "Invalid properties",
+				fasthttp.StatusUnprocessableEntity: "Camera rejected some values, body is the result",
+				fasthttp.StatusServiceUnavailable:  "Camera is not open",
+			},
+		},
+		{
+			Method: "GET", Path: "/api/v1/preprocess", Role: RoleViewer,
+			Handler: server.getParams(server.preprocessParams()),
+			Summary: "Preprocessing steps applied to the frame before detection", Body: app.PreprocessParams{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/preprocess", Role: RoleOperator,
+			Handler: server.setParams(server.preprocessParams()),
+			Summary: "Change preprocessing steps, body is merged into current steps",
+			Request: app.PreprocessParams{}, Body: app.PreprocessParams{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid parameters"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/masks", Role: RoleViewer, Handler: server.GetMasks,
+			Summary: "Include and exclude zones in normalised frame coordinates", Body: app.MaskParams{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/masks", Role: RoleOperator, Handler: server.SetMasks,
+			Summary: "Replace zones, detection runs only inside include zones, " +
+				"candidates with centroid inside exclude zones are rejected",
+			Request: app.MaskParams{}, Body: app.MaskParams{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid zones"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/quality", Role: RoleViewer,
+			Handler: server.getParams(server.qualityParams()),
+			Summary: "Thresholds of the frame quality gate", Body: app.QualityParams{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/quality", Role: RoleOperator,
+			Handler: server.setParams(server.qualityParams()),
+			Summary: "Change thresholds of the frame quality gate, body is merged into current thresholds",
+			Request: app.QualityParams{}, Body: app.QualityParams{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid thresholds"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/acquisition", Role: RoleViewer,
+			Handler: server.getParams(server.acquisitionParams()),
+			Summary: "Confirmation window of the target: required verified frames of the last window frames",
+			Body:    app.AcquisitionParams{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/acquisition", Role: RoleOperator,
+			Handler: server.setParams(server.acquisitionParams()),
+			Summary: "Change confirmation window, body is merged into current parameters, acquisition starts over",
+			Request: app.AcquisitionParams{}, Body: app.AcquisitionParams{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid parameters"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/search", Role: RoleViewer,
+			Handler: server.getParams(server.searchParams()),
+			Summary: "Search window around the previous target", Body: app.SearchParams{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/search", Role: RoleOperator,
+			Handler: server.setParams(server.searchParams()),
+			Summary: "Change search window, body is merged into current parameters",
+			Request: app.SearchParams{}, Body: app.SearchParams{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid parameters"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/shape", Role: RoleViewer,
+			Handler: server.getParams(server.shapeParams()),
+			Summary: "Contour geometry thresholds of every sign", Body: app.ShapeThresholds{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/shape", Role: RoleOperator,
+			Handler: server.setParams(server.shapeParams()),
+			Summary: "Change contour geometry thresholds, body is merged into current thresholds",
+			Request: app.ShapeThresholds{}, Body: app.ShapeThresholds{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid thresholds"},
+		},
+		{
+			Method: "GET", Path: "/api/v1/grouping", Role: RoleViewer,
+			Handler: server.getParams(server.groupingParams()),
+			Summary: "Grouping of overlapping cascade hits: NMS or OpenCV rectangles clustering",
+			Body:    app.GroupingParams{},
+		},
+		{
+			Method: "POST", Path: "/api/v1/grouping", Role: RoleOperator,
+			Handler: server.setParams(server.groupingParams()),
+			Summary: "Change grouping of cascade hits, body is merged into current parameters",
+			Request: app.GroupingParams{}, Body: app.GroupingParams{},
+			Errors: map[int]string{fasthttp.StatusBadRequest: "Invalid parameters"},
+		},
+		{
+			Method: "GET", Path: "/openapi.json", Handler: server.GetOpenAPI,
+			Summary: "OpenAPI document of the Web Server", Body: map[string]interface{}{},
+		},
+		{
+			Method: "GET", Path: "/", Handler: server.GetDashboard,
+			Summary: "Operator dashboard", ContentType: "text/html; charset=utf-8",
+		},
+	}
+}
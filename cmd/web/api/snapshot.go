@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/valyala/fasthttp"
+)
+
+// queryInt parses optional integer query argument
+func queryInt(ctx *fasthttp.RequestCtx, name string) (int, error) {
+	value := string(ctx.QueryArgs().Peek(name))
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// queryString returns query argument or default value
+func queryString(ctx *fasthttp.RequestCtx, name string, def string) string {
+	if value := string(ctx.QueryArgs().Peek(name)); value != "" {
+		return value
+	}
+	return def
+}
+
+// GetSnapshot returns the latest processed frame as JPEG or PNG
+func (server *WebServer) GetSnapshot(ctx *fasthttp.RequestCtx) {
+	params := app.SnapshotParams{
+		Kind:   queryString(ctx, "kind", app.SnapshotAnnotated),
+		Format: queryString(ctx, "format", app.FormatJPEG),
+	}
+
+	var err error
+	if params.Width, err = queryInt(ctx, "width"); err != nil {
+		ctx.Error("width must be a number", fasthttp.StatusBadRequest)
+		return
+	}
+	if params.Height, err = queryInt(ctx, "height"); err != nil {
+		ctx.Error("height must be a number", fasthttp.StatusBadRequest)
+		return
+	}
+	if err := params.Validate(); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := server.application.Snapshot(params)
+	if err == app.ErrNoFrame {
+		ctx.Error(err.Error(), fasthttp.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetContentType(snapshot.ContentType)
+	ctx.Response.Header.Set("X-Frame-Id", strconv.FormatUint(snapshot.Frame, 10))
+	ctx.Response.Header.Set("X-Frame-Time", snapshot.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	ctx.SetBody(snapshot.Image)
+}
+
+// PostCapture starts saving the next frames with their detections
+func (server *WebServer) PostCapture(ctx *fasthttp.RequestCtx) {
+	var params app.CaptureParams
+	if err := json.Unmarshal(ctx.PostBody(), &params); err != nil {
+		ctx.Error("invalid capture request: "+err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if err := params.Validate(); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	status, err := server.application.StartCapture(params)
+	if err == app.ErrCaptureRunning {
+		ctx.Error(err.Error(), fasthttp.StatusConflict)
+		return
+	}
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		return
+	}
+
+	server.audit.Info("Capture started", "principal", principalOf(ctx).Name, "id", status.ID,
+		"frames", status.Frames, "remote", ctx.RemoteIP().String())
+	writeJSON(ctx, fasthttp.StatusAccepted, status)
+}
+
+// GetCaptures returns progress of recent capture jobs
+func (server *WebServer) GetCaptures(ctx *fasthttp.RequestCtx) {
+	writeJSON(ctx, fasthttp.StatusOK, server.application.CaptureJobs())
+}
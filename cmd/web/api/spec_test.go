@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RadiumByte/Robot-Server/pkg/client"
+)
+
+// specDocument is a part of OpenAPI document used by the test
+type specDocument struct {
+	Paths      map[string]map[string]specOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]map[string]interface{} `json:"schemas"`
+	} `json:"components"`
+}
+
+type specOperation struct {
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema map[string]interface{} `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema map[string]interface{} `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+// schemaFields collects JSON field paths of the schema, e.g. telemetry.fps
+func (doc *specDocument) schemaFields(schema map[string]interface{}, prefix string, res map[string]bool) {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	}
+	switch schema["type"] {
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		doc.schemaFields(items, prefix+"[]", res)
+	case "object":
+		if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			doc.schemaFields(additional, prefix+"{}", res)
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			res[prefix+"."+name] = true
+			doc.schemaFields(property.(map[string]interface{}), prefix+"."+name, res)
+		}
+	}
+}
+
+// typeFields collects JSON field paths of the Go type in the same form as schemaFields
+func typeFields(t reflect.Type, prefix string, res map[string]bool) {
+	if t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(json.RawMessage{}) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		typeFields(t.Elem(), prefix, res)
+	case reflect.Slice, reflect.Array:
+		typeFields(t.Elem(), prefix+"[]", res)
+	case reflect.Map:
+		typeFields(t.Elem(), prefix+"{}", res)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if field.PkgPath != "" || tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			if name == "" {
+				name = field.Name
+			}
+			res[prefix+"."+name] = true
+			typeFields(field.Type, prefix+"."+name, res)
+		}
+	}
+}
+
+// difference returns sorted keys of a, which are missing in b
+func difference(a map[string]bool, b map[string]bool) []string {
+	var res []string
+	for key := range a {
+		if !b[key] {
+			res = append(res, key)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// jsonSchema returns JSON schema of the first content type
+func jsonSchema(content map[string]struct {
+	Schema map[string]interface{} `json:"schema"`
+}) map[string]interface{} {
+	return content["application/json"].Schema
+}
+
+// TestClientMatchesSpec checks endpoints and JSON fields of the client against routes of the server
+func TestClientMatchesSpec(t *testing.T) {
+	body, err := buildSpec((&WebServer{}).routes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &specDocument{}
+	if err := json.Unmarshal(body, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range client.Operations() {
+		name := op.Method + " " + op.Path
+		spec, ok := doc.Paths[op.Path][strings.ToLower(op.Method)]
+		if !ok {
+			t.Errorf("%s: endpoint is missing on the server", name)
+			continue
+		}
+
+		if op.Body != nil {
+			var schema map[string]interface{}
+			for _, response := range spec.Responses {
+				if s := jsonSchema(response.Content); s != nil {
+					schema = s
+					break
+				}
+			}
+			serverFields, clientFields := make(map[string]bool), make(map[string]bool)
+			doc.schemaFields(schema, "", serverFields)
+			typeFields(reflect.TypeOf(op.Body), "", clientFields)
+			if missing := difference(serverFields, clientFields); len(missing) > 0 {
+				t.Errorf("%s: response fields are missing in the client: %s", name, strings.Join(missing, ", "))
+			}
+			if unknown := difference(clientFields, serverFields); len(unknown) > 0 {
+				t.Errorf("%s: client expects unknown response fields: %s", name, strings.Join(unknown, ", "))
+			}
+		}
+
+		if op.Request != nil {
+			if spec.RequestBody == nil {
+				t.Errorf("%s: server does not accept request body", name)
+				continue
+			}
+			serverFields, clientFields := make(map[string]bool), make(map[string]bool)
+			doc.schemaFields(jsonSchema(spec.RequestBody.Content), "", serverFields)
+			typeFields(reflect.TypeOf(op.Request), "", clientFields)
+			if unknown := difference(clientFields, serverFields); len(unknown) > 0 {
+				t.Errorf("%s: client sends unknown request fields: %s", name, strings.Join(unknown, ", "))
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+// NewServerTLS constructs TLS configuration of the Web Server
+// Non-empty clientCAFile enables mutual TLS: clients must present certificate signed by this CA
+func NewServerTLS(certFile string, keyFile string, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &tls.Config{}
+	res.MinVersion = tls.VersionTLS12
+	res.Certificates = []tls.Certificate{cert}
+
+	if clientCAFile != "" {
+		pool, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		res.ClientCAs = pool
+		res.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return res, nil
+}
+
+// loadCertPool reads PEM encoded CA certificates
+func loadCertPool(path string) (*x509.CertPool, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(body) {
+		return nil, errors.New(path + ": no PEM certificates found")
+	}
+	return pool, nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/testcert"
+)
+
+// tlsFixture stores generated CA and server files
+type tlsFixture struct {
+	dir      string
+	ca       *testcert.CA
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+func newTLSFixture(t *testing.T) *tlsFixture {
+	dir, err := ioutil.TempDir("", "robot-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := testcert.NewCA("operators")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ca.Issue("robot-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := &tlsFixture{dir: dir, ca: ca}
+	if res.certFile, err = testcert.WriteFile(dir, "server.pem", server.CertPEM); err != nil {
+		t.Fatal(err)
+	}
+	if res.keyFile, err = testcert.WriteFile(dir, "server-key.pem", server.KeyPEM); err != nil {
+		t.Fatal(err)
+	}
+	if res.caFile, err = testcert.WriteFile(dir, "ca.pem", ca.PEM); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// handshake connects client to a listener with server configuration
+// Error of the server side is returned, because TLS 1.3 client learns about rejection only on read
+func handshake(t *testing.T, server *tls.Config, client *tls.Config) error {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer conn.Close()
+		errs <- tls.Server(conn, server).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp4", listener.Addr().String(), client)
+	if err == nil {
+		conn.Close()
+	}
+	return <-errs
+}
+
+// clientConfig trusts the fixture CA and presents certificate, if it is given
+func (fixture *tlsFixture) clientConfig(t *testing.T, cert *testcert.Leaf) *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(fixture.ca.Cert)
+
+	res := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+	if cert != nil {
+		pair, err := cert.TLSCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Certificates = []tls.Certificate{pair}
+	}
+	return res
+}
+
+func TestServerTLS(t *testing.T) {
+	fixture := newTLSFixture(t)
+	defer os.RemoveAll(fixture.dir)
+
+	config, err := NewServerTLS(fixture.certFile, fixture.keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientAuth != tls.NoClientCert {
+		t.Fatalf("client certificates are required without client CA")
+	}
+	if err := handshake(t, config, fixture.clientConfig(t, nil)); err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+}
+
+func TestServerMutualTLS(t *testing.T) {
+	fixture := newTLSFixture(t)
+	defer os.RemoveAll(fixture.dir)
+
+	config, err := NewServerTLS(fixture.certFile, fixture.keyFile, fixture.caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handshake(t, config, fixture.clientConfig(t, nil)); err == nil {
+		t.Fatal("client without certificate is accepted")
+	}
+
+	operator, err := fixture.ca.Issue("operator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, config, fixture.clientConfig(t, operator)); err != nil {
+		t.Fatalf("client with certificate of the CA is rejected: %v", err)
+	}
+
+	other, err := testcert.NewCA("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, err := other.Issue("stranger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, config, fixture.clientConfig(t, stranger)); err == nil {
+		t.Fatal("client with certificate of unknown CA is accepted")
+	}
+}
+
+func TestServerTLSInvalidClientCA(t *testing.T) {
+	fixture := newTLSFixture(t)
+	defer os.RemoveAll(fixture.dir)
+
+	path, err := testcert.WriteFile(fixture.dir, "empty.pem", []byte("not a certificate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServerTLS(fixture.certFile, fixture.keyFile, path); err == nil {
+		t.Fatal("file without certificates is accepted as client CA")
+	}
+}
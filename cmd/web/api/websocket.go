@@ -0,0 +1,243 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Minimal RFC 6455 server, only text frames of unfragmented messages are supported
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// maxClientFrame limits size of messages from clients
+const maxClientFrame = 4096
+
+// upgradeWebsocket completes handshake and passes connection to handler
+func upgradeWebsocket(ctx *fasthttp.RequestCtx, handler func(conn net.Conn)) bool {
+	if !strings.EqualFold(string(ctx.Request.Header.Peek("Upgrade")), "websocket") ||
+		!strings.Contains(strings.ToLower(string(ctx.Request.Header.Peek("Connection"))), "upgrade") {
+		ctx.Error("WebSocket upgrade expected", fasthttp.StatusBadRequest)
+		return false
+	}
+
+	key := string(ctx.Request.Header.Peek("Sec-WebSocket-Key"))
+	if key == "" {
+		ctx.Error("Sec-WebSocket-Key is missing", fasthttp.StatusBadRequest)
+		return false
+	}
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	ctx.SetStatusCode(fasthttp.StatusSwitchingProtocols)
+	ctx.Response.Header.Set("Upgrade", "websocket")
+	ctx.Response.Header.Set("Connection", "Upgrade")
+	ctx.Response.Header.Set("Sec-WebSocket-Accept", base64.StdEncoding.EncodeToString(hash[:]))
+	ctx.Hijack(handler)
+	return true
+}
+
+// writeFrame sends a single unmasked frame
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame receives a single masked frame from client
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if !masked {
+		return 0, nil, errors.New("client frame is not masked")
+	}
+	if length > maxClientFrame {
+		return 0, nil, errors.New("client frame is too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
+
+// eventFilter is a message, which client sends to change subscription
+type eventFilter struct {
+	Types []string `json:"types"`
+}
+
+// parseTypes splits comma separated list of event types
+func parseTypes(list string) []string {
+	var res []string
+	for _, eventType := range strings.Split(list, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			res = append(res, eventType)
+		}
+	}
+	return res
+}
+
+// StreamEvents pushes Application events to WebSocket subscriber
+// Types are selected by "types" query argument, e.g. /events?types=frame,command
+// Client can change the filter by sending {"types": [...]}
+func (server *WebServer) StreamEvents(ctx *fasthttp.RequestCtx) {
+	types := parseTypes(string(ctx.QueryArgs().Peek("types")))
+
+	upgradeWebsocket(ctx, func(conn net.Conn) {
+		defer conn.Close()
+
+		events := server.application.Events()
+		sub := events.Subscribe(types)
+		defer events.Unsubscribe(sub)
+
+		server.log.Info("Event subscriber connected", "remote", conn.RemoteAddr().String())
+		defer server.log.Info("Event subscriber disconnected", "remote", conn.RemoteAddr().String())
+
+		// Writes from reader (pong, close) and from event loop are serialized
+		// done is closed when event loop stops, so reader never blocks on outgoing
+		outgoing := make(chan [2][]byte, 8)
+		closed := make(chan struct{})
+		done := make(chan struct{})
+		defer close(done)
+
+		reply := func(opcode byte, payload []byte) bool {
+			select {
+			case outgoing <- [2][]byte{{opcode}, payload}:
+				return true
+			case <-done:
+				return false
+			}
+		}
+
+		go func() {
+			defer close(closed)
+			reader := bufio.NewReader(conn)
+			for {
+				opcode, payload, err := readFrame(reader)
+				if err != nil {
+					return
+				}
+
+				switch opcode {
+				case opText:
+					var filter eventFilter
+					if err := json.Unmarshal(payload, &filter); err == nil {
+						sub.SetTypes(filter.Types)
+					}
+				case opPing:
+					if !reply(opPong, payload) {
+						return
+					}
+				case opClose:
+					reply(opClose, nil)
+					return
+				}
+			}
+		}()
+
+		// Every write has a deadline, so a stalled client can't hold the connection
+		send := func(opcode byte, payload []byte) error {
+			conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+			return writeFrame(conn, opcode, payload)
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				body, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				if err := send(opText, body); err != nil {
+					return
+				}
+			case frame := <-outgoing:
+				if err := send(frame[0][0], frame[1]); err != nil || frame[0][0] == opClose {
+					return
+				}
+			case <-closed:
+				// Reader queues the close reply before it stops, so the close handshake is completed
+				for {
+					select {
+					case frame := <-outgoing:
+						if err := send(frame[0][0], frame[1]); err != nil || frame[0][0] == opClose {
+							return
+						}
+					default:
+						return
+					}
+				}
+			case <-server.done:
+				send(opClose, nil)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"errors"
+	"image"
+	"sync"
+)
+
+// States of target acquisition
+const (
+	AcquisitionSearching  = "searching"
+	AcquisitionConfirming = "confirming"
+	AcquisitionLocked     = "locked"
+)
+
+// AcquisitionParams configures multi-frame confirmation of the target
+// Target is confirmed if it was verified on Required frames of last Window frames
+type AcquisitionParams struct {
+	Required int `json:"required"`
+	Window   int `json:"window"`
+
+	// MaxDistance is a maximal centroid shift between frames of one candidate
+	MaxDistance float64 `json:"max_distance"`
+}
+
+// DefaultAcquisitionParams returns 3 of 5 frames confirmation
+func DefaultAcquisitionParams() AcquisitionParams {
+	return AcquisitionParams{
+		Required:    3,
+		Window:      5,
+		MaxDistance: 150.0,
+	}
+}
+
+// Validate checks that target can be confirmed inside the window
+func (params AcquisitionParams) Validate() error {
+	if params.Window < 1 || params.Window > 100 {
+		return errors.New("window must be in 1..100")
+	}
+	if params.Required < 1 || params.Required > params.Window {
+		return errors.New("required must be in 1..window")
+	}
+	if params.MaxDistance <= 0 {
+		return errors.New("max_distance must be positive")
+	}
+	return nil
+}
+
+// AcquisitionStatus is a snapshot of acquisition state
+type AcquisitionStatus struct {
+	State     string          `json:"state"`
+	Confirmed int             `json:"confirmed"`
+	Required  int             `json:"required"`
+	Window    int             `json:"window"`
+	Target    image.Rectangle `json:"target"`
+}
+
+// Acquisition tracks a candidate until it persists long enough to be trusted
+type Acquisition struct {
+	mutex   sync.Mutex
+	params  AcquisitionParams
+	history []bool
+	target  image.Rectangle
+	state   string
+}
+
+// NewAcquisition constructs Acquisition in searching state
+func NewAcquisition(params AcquisitionParams) *Acquisition {
+	res := &Acquisition{}
+	res.params = params
+	res.state = AcquisitionSearching
+	return res
+}
+
+// Params returns current confirmation window
+func (acq *Acquisition) Params() AcquisitionParams {
+	acq.mutex.Lock()
+	defer acq.mutex.Unlock()
+
+	return acq.params
+}
+
+// SetParams changes confirmation window and restarts acquisition
+func (acq *Acquisition) SetParams(params AcquisitionParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	acq.mutex.Lock()
+	defer acq.mutex.Unlock()
+
+	acq.params = params
+	acq.reset()
+	return nil
+}
+
+// Reset restarts acquisition, previous target is forgotten
+func (acq *Acquisition) Reset() {
+	acq.mutex.Lock()
+	defer acq.mutex.Unlock()
+
+	acq.reset()
+}
+
+func (acq *Acquisition) reset() {
+	acq.history = nil
+	acq.target = image.Rectangle{}
+	acq.state = AcquisitionSearching
+}
+
+// confirmed counts verified frames in the window
+func (acq *Acquisition) confirmed() int {
+	count := 0
+	for _, verified := range acq.history {
+		if verified {
+			count++
+		}
+	}
+	return count
+}
+
+// Observe adds result of one frame and returns true when target is confirmed
+// Candidate is valid only if it passed hash verification
+// Target is locked only on a frame where it was verified, so the car never drives to a box of an older frame
+func (acq *Acquisition) Observe(candidate image.Rectangle, valid bool) bool {
+	acq.mutex.Lock()
+	defer acq.mutex.Unlock()
+
+	if valid && acq.confirmed() > 0 {
+		// Candidate, which jumped too far, is another object - start over
+		if distBetweenPoints(centerOf(candidate), centerOf(acq.target)) > acq.params.MaxDistance {
+			acq.history = nil
+		}
+	}
+
+	acq.history = append(acq.history, valid)
+	if len(acq.history) > acq.params.Window {
+		acq.history = acq.history[len(acq.history)-acq.params.Window:]
+	}
+	if valid {
+		acq.target = candidate
+	}
+
+	count := acq.confirmed()
+	if valid && count >= acq.params.Required {
+		acq.state = AcquisitionLocked
+		return true
+	}
+
+	if count > 0 {
+		acq.state = AcquisitionConfirming
+	} else {
+		acq.state = AcquisitionSearching
+	}
+	return false
+}
+
+// Target returns last verified candidate
+func (acq *Acquisition) Target() image.Rectangle {
+	acq.mutex.Lock()
+	defer acq.mutex.Unlock()
+
+	return acq.target
+}
+
+// Status returns snapshot of acquisition state
+func (acq *Acquisition) Status() AcquisitionStatus {
+	acq.mutex.Lock()
+	defer acq.mutex.Unlock()
+
+	return AcquisitionStatus{
+		State:     acq.state,
+		Confirmed: acq.confirmed(),
+		Required:  acq.params.Required,
+		Window:    acq.params.Window,
+		Target:    acq.target,
+	}
+}
+
+// SetAcquisition changes confirmation window of the target, acquisition starts over
+func (app *Application) SetAcquisition(params AcquisitionParams) error {
+	if err := app.Acquisition.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Acquisition changed", "required", params.Required, "window", params.Window,
+		"max_distance", params.MaxDistance)
+	return nil
+}
+
+// AcquisitionSettings returns confirmation window of the target
+func (app *Application) AcquisitionSettings() AcquisitionParams {
+	return app.Acquisition.Params()
+}
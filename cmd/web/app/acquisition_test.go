@@ -0,0 +1,89 @@
+package app
+
+import (
+	"image"
+	"testing"
+)
+
+func TestAcquisitionObserve(t *testing.T) {
+	target := image.Rect(100, 100, 150, 150)
+	moved := target.Add(image.Pt(10, 0))
+	far := target.Add(image.Pt(400, 0))
+	none := image.Rectangle{}
+
+	type frame struct {
+		candidate image.Rectangle
+		valid     bool
+		locked    bool
+		state     string
+	}
+
+	tests := []struct {
+		name   string
+		frames []frame
+		target image.Rectangle
+	}{
+		{
+			name: "required of window",
+			frames: []frame{
+				{target, true, false, AcquisitionConfirming},
+				{none, false, false, AcquisitionConfirming},
+				{target, true, false, AcquisitionConfirming},
+				{none, false, false, AcquisitionConfirming},
+				{moved, true, true, AcquisitionLocked},
+			},
+			target: moved,
+		},
+		{
+			name: "verified frames leave the window",
+			frames: []frame{
+				{target, true, false, AcquisitionConfirming},
+				{target, true, false, AcquisitionConfirming},
+				{none, false, false, AcquisitionConfirming},
+				{none, false, false, AcquisitionConfirming},
+				{none, false, false, AcquisitionConfirming},
+				{none, false, false, AcquisitionConfirming},
+				{target, true, false, AcquisitionConfirming},
+			},
+			target: target,
+		},
+		{
+			name: "jump starts over",
+			frames: []frame{
+				{target, true, false, AcquisitionConfirming},
+				{target, true, false, AcquisitionConfirming},
+				{far, true, false, AcquisitionConfirming},
+				{far, true, false, AcquisitionConfirming},
+				{far, true, true, AcquisitionLocked},
+			},
+			target: far,
+		},
+		{
+			name: "stale target is not locked",
+			frames: []frame{
+				{target, true, false, AcquisitionConfirming},
+				{target, true, false, AcquisitionConfirming},
+				{target, true, true, AcquisitionLocked},
+				{none, false, false, AcquisitionConfirming},
+				{moved, true, true, AcquisitionLocked},
+			},
+			target: moved,
+		},
+	}
+
+	for _, test := range tests {
+		acq := NewAcquisition(DefaultAcquisitionParams())
+		for i, frame := range test.frames {
+			locked := acq.Observe(frame.candidate, frame.valid)
+			if locked != frame.locked {
+				t.Fatalf("%s: frame %d: locked %v, expected %v", test.name, i, locked, frame.locked)
+			}
+			if state := acq.Status().State; state != frame.state {
+				t.Fatalf("%s: frame %d: state %q, expected %q", test.name, i, state, frame.state)
+			}
+		}
+		if acq.Target() != test.target {
+			t.Errorf("%s: target %v, expected %v", test.name, acq.Target(), test.target)
+		}
+	}
+}
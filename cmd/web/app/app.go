@@ -1,18 +1,19 @@
 package app
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"image"
 	"time"
 
-	"image/color"
 	"math"
-
 	"strconv"
+
 	"sync"
+	"sync/atomic"
 
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
 	"gocv.io/x/gocv"
-	"gocv.io/x/gocv/contrib"
 )
 
 const (
@@ -21,36 +22,97 @@ const (
 	YieldCascade  = 2
 )
 
+// signClasses lists all cascade types in order
+var signClasses = []int{StopCascade, CircleCascade, YieldCascade}
+
 // bufferEraser cleans input videostream from unnecessary frames
 // So, input camera FPS == processing FPS
-func bufferEraser(source *gocv.VideoCapture, m *sync.Mutex) {
+// Every read frame increments sequence number, so gaps in perception logs show dropped frames
+func (app *Application) bufferEraser(ctx context.Context, source *gocv.VideoCapture, m *sync.Mutex, seq *uint64) {
 
 	tmp := gocv.NewMat()
 	defer tmp.Close()
 
 	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
 		m.Lock()
-		_ = source.Read(&tmp)
+		ok := source.Read(&tmp)
+		atomic.AddUint64(seq, 1)
 		m.Unlock()
+		framesDropped.Inc("")
+
+		if ok && !tmp.Empty() {
+			app.recordCapture(tmp)
+		}
 	}
 
 }
 
 // RobotServer is an interface for accepting income commands from Web Server
 type RobotServer interface {
-	ProcessCommand(string)
+	ProcessCommand(string) error
 
 	ChangeBlocking(bool)
 	ChangeManual(bool)
 
 	ChangeCascade(int)
-	Start()
+	ChangeOverlay(int)
+	Start(context.Context)
+	Shutdown(time.Duration) error
+
+	Status() Status
+	LatestFrame() ([]byte, uint64)
+	Events() *EventBus
+	RunPreflight() *PreflightReport
+	Health() *HealthReport
+
+	Snapshot(SnapshotParams) (*Snapshot, error)
+	StartCapture(CaptureParams) (*CaptureStatus, error)
+	CaptureJobs() []CaptureStatus
+
+	StartRecording(RecordingParams) (*RecordingStatus, error)
+	StopRecording() *RecordingStatus
+	Recording() *RecordingStatus
+
+	CameraState() (*CameraState, error)
+	SetCamera(CameraSettings) (*CameraUpdate, error)
+
+	Preprocessing() PreprocessParams
+	SetPreprocess(PreprocessParams) error
+
+	MaskZones() MaskParams
+	SetMasks(MaskParams) error
+
+	QualityThresholds() QualityParams
+	SetQuality(QualityParams) error
+
+	SearchMode() SearchParams
+	SetSearch(SearchParams) error
+
+	ShapeThresholds() ShapeThresholds
+	SetShape(ShapeThresholds) error
+
+	GroupingSettings() GroupingParams
+	SetGrouping(GroupingParams) error
+
+	AcquisitionSettings() AcquisitionParams
+	SetAcquisition(AcquisitionParams) error
 }
 
 // RobotAccessLayer is an interface for RAL usage from Application
 type RobotAccessLayer interface {
 	Turn(int)
 	DirectCommand(string)
+	Halt(int) error
+	Probe() error
+
+	LastSuccess() time.Time
+	ConsecutiveFailures() int
 }
 
 // Application is responsible for all logics and communicates with other layers
@@ -60,27 +122,67 @@ type Application struct {
 	IsBlocked   bool
 	CascadeType int
 	ReadMutex   sync.Mutex
+
+	Grouping *Grouping
+	Gallery  *Gallery
+	Shape    *ShapeVerifier
+	Hash     *HashVerifier
+	Stats    *StageStats
+
+	Acquisition *Acquisition
+	Captures    *Captures
+	Recorder    *Recorder
+	Camera      *Camera
+	Preprocess  *Preprocessor
+	Masks       *Masks
+	Quality     *QualityGate
+	Search      *Search
+
+	Overlay   int
+	frames    frameBuffer
+	capture   captureInfo
+	loop      loopMeter
+	telemetry telemetryState
+
+	Preflight       PreflightParams
+	preflightMutex  sync.Mutex
+	preflightReport *PreflightReport
+
+	Log           *logger.Entry
+	perceptionLog *logger.Entry
+	events        *EventBus
+
+	// done is closed when AI process stops
+	done chan struct{}
+
+	// Car is halted once, as soon as the program is stopping
+	stopOnce sync.Once
+	stopErr  error
 }
 
 // ChangeBlocking can block/unblock car movements
 func (app *Application) ChangeBlocking(mode bool) {
 	app.IsBlocked = mode
+	app.updateModeGauge()
+	app.publishMode()
 
 	if mode {
-		fmt.Println("Car is blocked")
+		app.Log.Info("Car is blocked")
 	} else {
-		fmt.Println("Car is moving")
+		app.Log.Info("Car is moving")
 	}
 }
 
 // ChangeBlocking sets current mode of driving
 func (app *Application) ChangeManual(mode bool) {
 	app.IsManual = mode
+	app.updateModeGauge()
+	app.publishMode()
 
 	if mode {
-		fmt.Println("Car is on manual control")
+		app.Log.Info("Car is on manual control")
 	} else {
-		fmt.Println("Car is driving automatically")
+		app.Log.Info("Car is driving automatically")
 	}
 }
 
@@ -90,17 +192,58 @@ func (app *Application) ChangeManual(mode bool) {
 // 2 - trapeze
 func (app *Application) ChangeCascade(cascade int) {
 	app.CascadeType = cascade
+	app.publishMode()
 	if cascade == StopCascade {
-		fmt.Println("Cascade type changed to Stop Sign")
+		app.Log.Info("Cascade type changed to Stop Sign")
 	} else if cascade == CircleCascade {
-		fmt.Println("Cascade type changed to Circle Sign")
+		app.Log.Info("Cascade type changed to Circle Sign")
 	} else if cascade == YieldCascade {
-		fmt.Println("Cascade type changed to Yield Sign")
+		app.Log.Info("Cascade type changed to Yield Sign")
+	}
+}
+
+// ChangeOverlay selects what is drawn over the autopilot view
+// 0 - final target only
+// 1 - whole filter funnel with HUD
+func (app *Application) ChangeOverlay(mode int) {
+	app.Overlay = mode
+	app.publishMode()
+	if mode == OverlayFunnel {
+		app.Log.Info("Debug overlay enabled")
+	} else {
+		app.Log.Info("Debug overlay disabled")
 	}
 }
 
+// Events returns bus of Application events
+func (app *Application) Events() *EventBus {
+	return app.events
+}
+
+// reportError logs error and publishes it to subscribers
+func (app *Application) reportError(message string, err error) {
+	if err != nil {
+		app.Log.Error(message, "err", err)
+		message += ": " + err.Error()
+	} else {
+		app.Log.Error(message)
+	}
+	app.events.Publish(EventError, MessageEvent{Message: message})
+}
+
+// modeName describes current driving mode for HUD
+func (app *Application) modeName() string {
+	if app.IsManual {
+		return "manual"
+	}
+	if app.IsBlocked {
+		return "blocked"
+	}
+	return "auto, " + app.Acquisition.Status().State
+}
+
 // ProcessCommand parses command and determines what to do with it
-func (app *Application) ProcessCommand(command string) {
+func (app *Application) ProcessCommand(command string) error {
 	if command == "halt" {
 		app.ChangeBlocking(true)
 		app.Robot.DirectCommand("HALT")
@@ -113,6 +256,10 @@ func (app *Application) ProcessCommand(command string) {
 		//app.Robot.DirectCommand("HALT")
 
 	} else if command == "auto" {
+		if report := app.lastPreflight(); report != nil && !report.Passed {
+			app.Log.Warn("Auto mode refused: preflight failed")
+			return errors.New("auto mode refused: preflight failed")
+		}
 		app.ChangeManual(false)
 		app.Robot.DirectCommand("HALT")
 
@@ -128,46 +275,156 @@ func (app *Application) ProcessCommand(command string) {
 		app.ChangeCascade(YieldCascade)
 		//app.Robot.DirectCommand("HALT")
 
-	} else {
+	} else if command == "debugon" {
+		app.ChangeOverlay(OverlayFunnel)
+
+	} else if command == "debugoff" {
+		app.ChangeOverlay(OverlayTarget)
+
+	} else if len(command) > 1 && (command[0] == 'S' || command[0] == 'F' || command[0] == 'B') {
 		// Manual control block
-		if command[0] == 'S' || command[0] == 'F' || command[0] == 'B' {
-			if !app.IsManual {
-				if app.IsManual {
-					//app.Robot.DirectCommand(command)
-				}
-			}
+		return app.manualDrive(command)
+
+	} else {
+		return errors.New("unknown command: " + command)
+	}
+
+	return nil
+}
+
+// manualDrive sends steering (S0..S100) or throttle (F0..F100, B0..B100) command
+// Commands are accepted only on manual control, when car is not blocked
+func (app *Application) manualDrive(command string) error {
+	if !app.IsManual {
+		return errors.New("manual command refused: car is driving automatically")
+	}
+	if app.IsBlocked {
+		return errors.New("manual command refused: car is blocked")
+	}
+
+	value, err := strconv.Atoi(command[1:])
+	if err != nil || value < 0 || value > 100 {
+		return errors.New("invalid manual command: " + command)
+	}
+
+	if command[0] == 'S' {
+		app.Robot.Turn(value)
+		app.setManualSteering(value)
+	} else {
+		app.Robot.DirectCommand(command[:1] + strconv.Itoa(value))
+		if command[0] == 'B' {
+			value = -value
 		}
+		app.setManualThrottle(value)
 	}
+	return nil
 }
 
 func distBetweenPoints(from image.Point, to image.Point) float64 {
 	return math.Sqrt(float64((to.X-from.X)*(to.X-from.X) + (to.Y-from.Y)*(to.Y-from.Y)))
 }
 
+func centerOf(rect image.Rectangle) image.Point {
+	return image.Pt((rect.Dx()/2)+rect.Min.X, (rect.Dy()/2)+rect.Min.Y)
+}
+
 // NewApplication constructs Application
-func NewApplication(robot RobotAccessLayer) (*Application, error) {
+func NewApplication(robot RobotAccessLayer, log *logger.Logger) (*Application, error) {
 	res := &Application{}
-	res.Robot = robot
+	res.events = NewEventBus()
+	res.Robot = &eventRobot{RobotAccessLayer: robot, events: res.events}
+	res.Log = log.Component("app")
+	res.perceptionLog = log.Component("perception")
 	res.CascadeType = StopCascade
 	res.IsBlocked = true
 	res.IsManual = false
+	res.Preflight = DefaultPreflightParams()
+	res.updateModeGauge()
+	res.Grouping = NewGrouping()
+	res.Shape = NewShapeVerifier()
+	res.Stats = NewStageStats()
+	res.Acquisition = NewAcquisition(DefaultAcquisitionParams())
+	res.Captures = NewCaptures("captures")
+	res.Masks = NewMasks()
+	res.Search = NewSearch()
+	res.Recorder = NewRecorder("recordings")
+	res.Camera = NewCamera(videoSource, "camera-profiles.json")
+	res.frames.raw = gocv.NewMat()
+	res.frames.annotated = gocv.NewMat()
+
+	preprocess, err := NewPreprocessor(DefaultPreprocessParams())
+	if err != nil {
+		return nil, err
+	}
+	res.Preprocess = preprocess
+
+	quality, err := NewQualityGate(DefaultQualityParams())
+	if err != nil {
+		return nil, err
+	}
+	res.Quality = quality
+
+	hash, err := NewHashVerifier()
+	if err != nil {
+		return nil, err
+	}
+	res.Hash = hash
+
+	gallery, err := LoadGallery(DefaultGalleryPaths())
+	if err != nil {
+		return nil, err
+	}
+	res.Gallery = gallery
+
+	// Reference hashes are computed only once
+	for cascade, references := range gallery.Images {
+		if err := res.Hash.SetReferences(cascade, references); err != nil {
+			gallery.Close()
+			return nil, err
+		}
+	}
 
 	return res, nil
 }
 
-func (app *Application) ai() {
+func (app *Application) ai(ctx context.Context) {
 
-	webcam, err := gocv.OpenVideoCapture(0)
+	webcam, err := gocv.OpenVideoCapture(app.Camera.Index)
 	if err != nil {
-		fmt.Println(err)
+		app.reportError("Videostream can not be opened", err)
 		return
 	}
 	defer webcam.Close()
-	fmt.Println("RTSP videostream claimed...")
+	app.Log.Info("RTSP videostream claimed")
 
-	var m sync.Mutex
-	go bufferEraser(webcam, &m)
-	fmt.Println("Buffer eraser started...")
+	// Camera is controlled by API only while it is open
+	update, err := app.Camera.attach(webcam)
+	if err != nil {
+		app.reportError("Camera profile can not be loaded", err)
+	} else if update != nil {
+		app.logCameraUpdate("Camera profile applied", update)
+	}
+	defer app.Camera.detach()
+
+	// seq counts every frame read from the camera, frameID counts processed frames
+	var seq uint64
+	var frameID uint64
+
+	// Buffer eraser must stop before webcam is closed
+	// Reads share the lock of the camera, so properties are not changed in the middle of a read
+	m := &app.Camera.mutex
+	eraserCtx, stopEraser := context.WithCancel(ctx)
+	eraserDone := make(chan struct{})
+	go func() {
+		app.bufferEraser(eraserCtx, webcam, m, &seq)
+		close(eraserDone)
+	}()
+	defer func() {
+		stopEraser()
+		<-eraserDone
+		app.Log.Info("Buffer eraser stopped")
+	}()
+	app.Log.Info("Buffer eraser started")
 
 	window := gocv.NewWindow("Autopilot")
 	defer window.Close()
@@ -175,360 +432,152 @@ func (app *Application) ai() {
 	imgCurrent := gocv.NewMat()
 	defer imgCurrent.Close()
 
-	imgTarget := gocv.NewMat()
-	defer imgTarget.Close()
-
-	imgStopSign := gocv.NewMat()
-	defer imgStopSign.Close()
-	imgStopSign = gocv.IMRead("stop.jpg", 0)
-
-	imgCircleSign := gocv.NewMat()
-	defer imgCircleSign.Close()
-	imgCircleSign = gocv.IMRead("circle.jpg", 0)
-
-	imgYieldSign := gocv.NewMat()
-	defer imgYieldSign.Close()
-	imgYieldSign = gocv.IMRead("yield.jpg", 0)
-
-	cascadeCircle := gocv.NewCascadeClassifier()
-	cascadeCircle.Load("circle.xml")
-
-	cascadeStop := gocv.NewCascadeClassifier()
-	cascadeStop.Load("stop.xml")
-
-	cascadeTrapeze := gocv.NewCascadeClassifier()
-	cascadeTrapeze.Load("yield.xml")
-
-	// Color of bounding box around the target
-	blue := color.RGBA{0, 0, 255, 0}
-
-	// "Memory" about previous correct target
-	// It is a component of noise supperssion
-	var prevTargetCenter image.Point
-	var prevTargetSquare float64
-
-	// Target detection on the first step varies from others
-	var isFirstIteration bool = true
-
-	// Car behaviour configuration
-	// Change car's speed while driving forward
-	var maxThrottle int = 70
-	var minThrottle int = 15
-
-	// Change distances for min and max speed
-	// Min speed:
-	var maxSquare float64 = 85 * 85
-	// Max speed:
-	var minSquare float64 = 35 * 35
-
-	// Memory about last car's movement
-	// Need it for reducing network load
-	var prevThrottle int = 0
-	var prevSteering int = 50
-
-	// How fast car will accelerate backward
-	var backwardAcceleration float64 = 650.0
-
-	// Max speed for driving backward
-	var maxBackwardThrottle int = 60
-
-	// Constants for object filtering
-	// They are choosing automatically, don't set them here
-	var MAX_DISTANCE_DIFF float64
-	var MAX_SQUARE_DIFF float64
-	var MAX_SIMILARITY_RATE float64
-	var MIN_SIMILARITY_RATE float64
+	cascades, err := loadCascades()
+	if err != nil {
+		app.reportError("Cascades can not be loaded", err)
+		return
+	}
+	defer closeCascades(cascades)
 
-	comparator := contrib.ColorMomentHash{}
+	pilot := newAutopilot(app, app.perceptionLog, cascades)
 
-	failureCounter := 0
+	// FPS is smoothed to make HUD readable
+	var fps float64
+	lastFrameTime := time.Now()
 
 	m.Lock()
 	_ = webcam.Read(&imgCurrent)
 	m.Unlock()
 
-	fmt.Println("Main loop is starting...")
+	app.Log.Info("Main loop is starting")
 	for {
+		select {
+		case <-ctx.Done():
+			app.Log.Info("Main loop is stopped")
+			return
+		default:
+		}
+
 		if !app.IsManual {
+			var report *FrameReport
+
 			if !app.IsBlocked {
 
+				captureStart := time.Now()
 				m.Lock()
 				ok := webcam.Read(&imgCurrent)
+				frameSeq := atomic.AddUint64(&seq, 1)
 				m.Unlock()
+				stageLatency.Since(latencyCapture, captureStart)
 
 				if !ok {
-					fmt.Printf("Error while read RTSP: program aborted...")
+					app.reportError("Error while read RTSP: program aborted", nil)
 					return
 				}
 
 				if imgCurrent.Empty() {
+					framesDropped.Inc("")
+					app.loop.tick(false)
 					continue
 				}
-
-				// rawObjects stores everything, which Haar Cascade returned, including noise
-				var rawObjects []image.Rectangle
-
-				if app.CascadeType == StopCascade {
-					// Stop cascade
-					rawObjects = cascadeStop.DetectMultiScale(imgCurrent)
-
-					MAX_DISTANCE_DIFF = 400.0
-					MAX_SQUARE_DIFF = 40000.0
-					MAX_SIMILARITY_RATE = 100.0
-					MIN_SIMILARITY_RATE = 1.0
-
-				} else if app.CascadeType == CircleCascade {
-					// Circle cascade
-					rawObjects = cascadeCircle.DetectMultiScale(imgCurrent)
-
-					MAX_DISTANCE_DIFF = 200.0
-					MAX_SQUARE_DIFF = 20000.0
-					MAX_SIMILARITY_RATE = 35.0
-					MIN_SIMILARITY_RATE = 1.0
-
-				} else if app.CascadeType == YieldCascade {
-					// Yield cascade
-					rawObjects = cascadeTrapeze.DetectMultiScale(imgCurrent)
-
-					MAX_DISTANCE_DIFF = 600.0
-					MAX_SQUARE_DIFF = 60000.0
-					MAX_SIMILARITY_RATE = 140.0
-					MIN_SIMILARITY_RATE = 1.0
-				}
-
-				if failureCounter >= 5 {
-					// All normal targets disappeared, so car halts
-					app.Robot.DirectCommand("HALT")
-					isFirstIteration = true
-				}
-
-				if len(rawObjects) == 0 {
-					fmt.Println("Cascade returned empty result")
-					failureCounter = failureCounter + 1
-					continue
-				}
-
-				// nearObjects stores targets, which passed geometrical conditions
-				var nearObjects []image.Rectangle
-
-				// trustedObjects stores targets, which passed content and geometrical conditions
-				var trustedObjects []image.Rectangle
-
-				if isFirstIteration {
-					// At first iteration simply determine biggest object
-					// It is unsafe, but works only once
-
-					// Max square calculation
-					maxSquareIndex := -1
-					maxSquare := 0.0
-					for i, rect := range rawObjects {
-						currentSquare := float64(rect.Dx() * rect.Dy())
-						if currentSquare > maxSquare {
-							maxSquare = currentSquare
-							maxSquareIndex = i
-						}
-					}
-					trustedObjects = append(trustedObjects, rawObjects[maxSquareIndex])
-
-				} else {
-					// Generic multi-step filtering, enables after first iteration
-					// First step - find geometrically close objects by their square and location
-
-					for _, rect := range rawObjects {
-						square := rect.Dx() * rect.Dy()
-						var centroidCurrent image.Point
-						centroidCurrent.X = (rect.Dx() / 2) + rect.Min.X
-						centroidCurrent.Y = (rect.Dy() / 2) + rect.Min.Y
-
-						fmt.Print("Distance between centers: ")
-						fmt.Println(distBetweenPoints(centroidCurrent, prevTargetCenter))
-						fmt.Print("Squares difference: ")
-						fmt.Println(math.Abs(float64(square) - prevTargetSquare))
-
-						if distBetweenPoints(centroidCurrent, prevTargetCenter) < MAX_DISTANCE_DIFF {
-							if math.Abs(float64(square)-prevTargetSquare) < MAX_SQUARE_DIFF {
-								nearObjects = append(nearObjects, rect)
-							}
-						}
-					}
-
-					// Second step - usage of Color Moment Hash to compare target with preloaded model
-					computedCurrent := gocv.NewMat()
-					defer computedCurrent.Close()
-
-					computedTarget := gocv.NewMat()
-					defer computedTarget.Close()
-
-					regionTarget := gocv.NewMat()
-					defer regionTarget.Close()
-
-					// Precalculated models
-					if app.CascadeType == StopCascade {
-						comparator.Compute(imgStopSign, &computedTarget)
-					} else if app.CascadeType == CircleCascade {
-						comparator.Compute(imgCircleSign, &computedTarget)
-					} else if app.CascadeType == YieldCascade {
-						comparator.Compute(imgYieldSign, &computedTarget)
-					}
-
-					for _, rect := range nearObjects {
-						regionCurrent := gocv.NewMat()
-						defer regionCurrent.Close()
-						regionCurrent = imgCurrent.Region(rect)
-
-						comparator.Compute(regionCurrent, &computedCurrent)
-						similarity := comparator.Compare(computedCurrent, computedTarget)
-						fmt.Print("CMH similarity: ")
-						fmt.Printf("%0.4f\n", similarity)
-
-						if similarity < MAX_SIMILARITY_RATE && similarity > MIN_SIMILARITY_RATE {
-							trustedObjects = append(trustedObjects, rect)
-						}
-					}
-				}
-
-				if len(trustedObjects) == 0 {
-					fmt.Println("No trusted objects found")
-					failureCounter = failureCounter + 1
-					continue
+				app.recordCapture(imgCurrent)
+
+				// Raw frame is kept for snapshots before the autopilot undistorts it and overlay is drawn
+				frameID++
+				app.storeRaw(imgCurrent, frameID)
+				processStart := time.Now()
+				report = pilot.process(imgCurrent, frameID, frameSeq)
+				framesProcessed.Inc("")
+				app.loop.tick(true)
+
+				// Captures keep the preprocessed frame before overlay is drawn, detections are in its coordinates
+				event := app.frameEventOf(report, frameID, frameSeq, time.Since(processStart))
+				app.events.Publish(EventFrame, event)
+				app.Captures.offer(imgCurrent, event)
+
+				now := time.Now()
+				if elapsed := now.Sub(lastFrameTime).Seconds(); elapsed > 0 {
+					fps = 0.9*fps + 0.1/elapsed
 				}
-
-				// finalObject stores only one target, selected by filter
-				var finalObject image.Rectangle
-
-				// This situation occurs if several good targets found
-				// Need to find largest (closest) good target
-				if len(trustedObjects) > 1 {
-					maxSquareIndex := -1
-					maxSquare := 0.0
-					for i, rect := range trustedObjects {
-						currentSquare := float64(rect.Dx() * rect.Dy())
-						if currentSquare > maxSquare {
-							maxSquare = currentSquare
-							maxSquareIndex = i
-						}
-					}
-					finalObject = trustedObjects[maxSquareIndex]
-				} else if len(trustedObjects) == 1 {
-					finalObject = trustedObjects[0]
-				}
-
-				// This counter checks how many times cascade returned empty data
-				failureCounter = 0
-
-				// Car throttle logic
-				// Throttle depends on a distance to target
-				// Bigger square - lower throttle down to full stop
-
-				// Actual size of the target
-				targetSquare := float64(finalObject.Dx() * finalObject.Dy())
-				fmt.Print("Target square: ")
-				fmt.Println(targetSquare)
-
-				if targetSquare > maxSquare {
-					// Target is too close - car is going backward
-
-					deltaSquare := targetSquare - maxSquare
-					calculatedThrottle := int(deltaSquare / backwardAcceleration)
-
-					if calculatedThrottle > maxBackwardThrottle {
-						calculatedThrottle = maxBackwardThrottle
-					}
-
-					calculatedThrottleStr := strconv.Itoa(calculatedThrottle)
-					app.Robot.DirectCommand("B" + calculatedThrottleStr)
-				} else {
-					// Target in range - car is going forward
-
-					targetSquareInRange := maxSquare - targetSquare
-					deltaThrottle := maxThrottle - minThrottle
-					deltaSquare := math.Abs(maxSquare - minSquare)
-
-					calculatedThrottle := int(((float64(deltaThrottle) * targetSquareInRange) / deltaSquare) + float64(minThrottle))
-
-					if calculatedThrottle > maxThrottle {
-						calculatedThrottle = maxThrottle
-					}
-
-					// Throttle sensivity
-					// If throttle is almost the same as previous - no need to send command again
-					if math.Abs(float64(calculatedThrottle-prevThrottle)) > 2 {
-						prevThrottle = calculatedThrottle
-						calculatedThrottleStr := strconv.Itoa(calculatedThrottle)
-						app.Robot.DirectCommand("F" + calculatedThrottleStr)
-					}
-				}
-
-				// Car steering logic
-				// Horizontal position of target influences on wheels steering
-				var command int
-
-				// Calculate center of the target
-				var centroid image.Point
-				centroid.X = (finalObject.Dx() / 2) + finalObject.Min.X
-				centroid.Y = (finalObject.Dy() / 2) + finalObject.Min.Y
-
-				// Refresh previous center and square
-				prevTargetCenter = centroid
-				prevTargetSquare = float64(finalObject.Dx() * finalObject.Dy())
-
-				isFirstIteration = false
-
-				// Borders determine tube area in the center of frame
-				// In this area car won't steer in any directions
-				rightBorder := int(float64(imgCurrent.Cols()) * 0.52)
-				leftBorder := int(float64(imgCurrent.Cols()) * 0.48)
-
-				if centroid.X >= leftBorder && centroid.X <= rightBorder {
-					// Target in tube - need to ride forward
-					command = 50
-
-				} else if centroid.X < leftBorder {
-					// Need to steer left
-					command = (50 * centroid.X) / leftBorder
-
-				} else if centroid.X > rightBorder {
-					// Need to steer right
-					command = ((50 * centroid.X) / (imgCurrent.Cols() - rightBorder)) - 12
-				}
-
-				// Max turning if target is going to escape frame
-				// In theory, this will increase steering ability
-				if command >= 80 {
-					command = 100
-				} else if command <= 20 {
-					command = 0
-				}
-
-				// Steering sensivity
-				// If steering is almost the same as previous - no need to send command again
-				if math.Abs(float64(command-prevSteering)) > 2 {
-					prevSteering = command
-					app.Robot.Turn(command)
-				}
-
-				// Draw bounding box and show it
-				gocv.Rectangle(&imgCurrent, finalObject, blue, 3)
-				size := gocv.GetTextSize("Target", gocv.FontHersheyPlain, 1.2, 2)
-				pt := image.Pt(finalObject.Min.X+(finalObject.Min.X/2)-(size.X/2), finalObject.Min.Y-2)
-				gocv.PutText(&imgCurrent, "Target", pt, gocv.FontHersheyPlain, 1.2, blue, 2)
+				lastFrameTime = now
 			} else {
+				app.loop.tick(false)
 				time.Sleep(1 * time.Millisecond)
 			}
 
+			if report != nil {
+				app.setTelemetry(report, fps)
+				drawOverlay(&imgCurrent, report, app.Overlay, HUD{Mode: app.modeName(), FPS: fps})
+				app.publishFrame(imgCurrent)
+				app.Recorder.offer(imgCurrent, frameID)
+			}
+
 			window.IMShow(imgCurrent)
 			if window.WaitKey(1) >= 0 {
 				break
 			}
 
 		} else {
+			app.loop.tick(false)
 			time.Sleep(1 * time.Millisecond)
 		}
 
 	}
 }
 
-// Start initializes AI process
-func (app *Application) Start() {
-	go app.ai()
+// Start initializes AI process, which runs until context is cancelled
+func (app *Application) Start(ctx context.Context) {
+	app.done = make(chan struct{})
+	go func() {
+		defer close(app.done)
+		app.ai(ctx)
+	}()
+}
+
+// Stop waits for AI process to stop, blocks autopilot and halts the car
+// Context passed to Start must be cancelled before
+// It is called as soon as the program is stopping, so open connections can't delay the halt
+func (app *Application) Stop(timeout time.Duration) error {
+	app.stopOnce.Do(func() {
+		app.IsBlocked = true
+		app.updateModeGauge()
+
+		if app.done != nil {
+			select {
+			case <-app.done:
+			case <-time.After(timeout):
+				app.stopErr = errors.New("AI process did not stop in time")
+				app.reportError("AI process did not stop in time", nil)
+			}
+		}
+
+		if err := app.Robot.Halt(5); err != nil {
+			app.reportError("Car was not halted", err)
+			app.stopErr = err
+		} else {
+			app.Log.Info("Car is halted")
+		}
+	})
+	return app.stopErr
+}
+
+// Shutdown stops AI process and the car, if it is not done yet, and releases resources
+// Context passed to Start must be cancelled before
+func (app *Application) Shutdown(timeout time.Duration) error {
+	res := app.Stop(timeout)
+
+	// Queued frames of the capture and the recording are written before exit
+	app.Captures.Close()
+	app.Recorder.Close()
+
+	// Resources are released only when AI process does not use them
+	if !app.isRunning() {
+		app.Hash.Close()
+		app.Gallery.Close()
+		app.Preprocess.Close()
+		app.frames.raw.Close()
+		app.frames.annotated.Close()
+	}
+
+	return res
 }
@@ -0,0 +1,331 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"gocv.io/x/gocv"
+)
+
+// cameraProperties maps names of the API to capture properties
+// Properties are applied in this order, so resolution is changed before frame rate
+var cameraProperties = []struct {
+	Name     string
+	Property gocv.VideoCaptureProperties
+}{
+	{"width", gocv.VideoCaptureFrameWidth},
+	{"height", gocv.VideoCaptureFrameHeight},
+	{"fps", gocv.VideoCaptureFPS},
+	{"buffer_size", gocv.VideoCaptureBufferSize},
+	{"auto_exposure", gocv.VideoCaptureAutoExposure},
+	{"exposure", gocv.VideoCaptureExposure},
+	{"gain", gocv.VideoCaptureGain},
+	{"white_balance_blue_u", gocv.VideoCaptureWhiteBalanceBlueU},
+	{"white_balance_red_v", gocv.VideoCaptureWhiteBalanceRedV},
+	{"brightness", gocv.VideoCaptureBrightness},
+}
+
+// ErrCameraClosed is returned when AI process does not hold the camera
+var ErrCameraClosed = errors.New("camera is not open")
+
+// CameraSettings are values of capture properties by their names
+type CameraSettings map[string]float64
+
+// CameraProperty is a result of changing a single property
+// Device may silently ignore or round the value, so it is read back after change
+type CameraProperty struct {
+	Name      string  `json:"name"`
+	Requested float64 `json:"requested"`
+	Actual    float64 `json:"actual"`
+	Applied   bool    `json:"applied"`
+}
+
+// CameraUpdate is a result of changing properties, rejected properties keep previous values
+type CameraUpdate struct {
+	Device     string           `json:"device"`
+	Applied    bool             `json:"applied"`
+	Properties []CameraProperty `json:"properties"`
+}
+
+// CameraState describes the camera and its saved profile
+type CameraState struct {
+	Device string `json:"device"`
+	Open   bool   `json:"open"`
+	// Properties are values reported by the device, they are empty when camera is not open
+	Properties CameraSettings `json:"properties"`
+	Profile    CameraSettings `json:"profile"`
+}
+
+// Camera controls properties of the video source used by AI process
+// Profiles are kept in a JSON file by device name, so every camera gets its own settings
+type Camera struct {
+	// Index is a number of the video device
+	Index int
+	// ProfilePath is a JSON file with profiles of all devices
+	ProfilePath string
+
+	// mutex guards reads from the device, property changes wait for the current read
+	mutex   sync.Mutex
+	capture *gocv.VideoCapture
+
+	profileMutex sync.Mutex
+}
+
+// NewCamera constructs Camera of the video device
+func NewCamera(index int, profilePath string) *Camera {
+	res := &Camera{}
+	res.Index = index
+	res.ProfilePath = profilePath
+	return res
+}
+
+// Validate checks names and values of properties
+func (settings CameraSettings) Validate() error {
+	if len(settings) == 0 {
+		return errors.New("no properties")
+	}
+	for name, value := range settings {
+		if !isCameraProperty(name) {
+			return errors.New("unknown property " + name + ", known properties: " + strings.Join(CameraPropertyNames(), ", "))
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return errors.New(name + " must be a number")
+		}
+		switch name {
+		case "width", "height", "fps", "buffer_size":
+			if value < 1 {
+				return errors.New(name + " must be positive")
+			}
+		}
+	}
+	return nil
+}
+
+// isCameraProperty checks if name is known
+func isCameraProperty(name string) bool {
+	for _, property := range cameraProperties {
+		if property.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CameraPropertyNames lists properties in order of their application
+func CameraPropertyNames() []string {
+	var res []string
+	for _, property := range cameraProperties {
+		res = append(res, property.Name)
+	}
+	return res
+}
+
+// sameValue checks value reported by the device, drivers round some properties
+func sameValue(requested float64, actual float64) bool {
+	return math.Abs(requested-actual) <= 0.01*math.Max(1, math.Abs(requested))
+}
+
+// Device returns name of the video device, profiles are stored by it
+func (camera *Camera) Device() string {
+	name := fmt.Sprintf("video%d", camera.Index)
+	if body, err := ioutil.ReadFile("/sys/class/video4linux/" + name + "/name"); err == nil {
+		if model := strings.TrimSpace(string(body)); model != "" {
+			return name + ":" + model
+		}
+	}
+	return name
+}
+
+// loadProfiles reads profiles of all devices, missing file means no profiles
+func (camera *Camera) loadProfiles() (map[string]CameraSettings, error) {
+	res := make(map[string]CameraSettings)
+	if camera.ProfilePath == "" {
+		return res, nil
+	}
+
+	body, err := ioutil.ReadFile(camera.ProfilePath)
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("%s: %v", camera.ProfilePath, err)
+	}
+	return res, nil
+}
+
+// Profile returns saved settings of the current device
+func (camera *Camera) Profile() (CameraSettings, error) {
+	camera.profileMutex.Lock()
+	defer camera.profileMutex.Unlock()
+
+	profiles, err := camera.loadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if profiles[camera.Device()] == nil {
+		return CameraSettings{}, nil
+	}
+	return profiles[camera.Device()], nil
+}
+
+// saveProfile merges settings into profile of the device
+func (camera *Camera) saveProfile(device string, settings CameraSettings) error {
+	if camera.ProfilePath == "" || len(settings) == 0 {
+		return nil
+	}
+
+	camera.profileMutex.Lock()
+	defer camera.profileMutex.Unlock()
+
+	profiles, err := camera.loadProfiles()
+	if err != nil {
+		return err
+	}
+	if profiles[device] == nil {
+		profiles[device] = CameraSettings{}
+	}
+	for name, value := range settings {
+		profiles[device][name] = value
+	}
+
+	body, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// File is replaced at once, so profiles are not lost if the server stops while writing
+	tmp := camera.ProfilePath + ".tmp"
+	if err := ioutil.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, camera.ProfilePath)
+}
+
+// apply changes properties in the fixed order and reads them back
+// Capture must not be read meanwhile, so shared capture is changed under the mutex
+func (camera *Camera) apply(capture *gocv.VideoCapture, settings CameraSettings) *CameraUpdate {
+	res := &CameraUpdate{Device: camera.Device(), Applied: true}
+	for _, property := range cameraProperties {
+		requested, ok := settings[property.Name]
+		if !ok {
+			continue
+		}
+
+		previous := capture.Get(property.Property)
+		capture.Set(property.Property, requested)
+		actual := capture.Get(property.Property)
+
+		result := CameraProperty{Name: property.Name, Requested: requested, Actual: actual, Applied: sameValue(requested, actual)}
+		if !result.Applied {
+			capture.Set(property.Property, previous)
+			result.Actual = capture.Get(property.Property)
+			res.Applied = false
+		}
+		res.Properties = append(res.Properties, result)
+	}
+	return res
+}
+
+// attach makes capture controllable and applies saved profile of the device
+func (camera *Camera) attach(capture *gocv.VideoCapture) (*CameraUpdate, error) {
+	profile, err := camera.Profile()
+
+	camera.mutex.Lock()
+	defer camera.mutex.Unlock()
+
+	camera.capture = capture
+	if err != nil || len(profile) == 0 {
+		return nil, err
+	}
+	return camera.apply(capture, profile), nil
+}
+
+// detach is called before capture is closed
+func (camera *Camera) detach() {
+	camera.mutex.Lock()
+	camera.capture = nil
+	camera.mutex.Unlock()
+}
+
+// Set changes properties of the open camera, applied values are saved to the profile
+func (camera *Camera) Set(settings CameraSettings) (*CameraUpdate, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	camera.mutex.Lock()
+	if camera.capture == nil {
+		camera.mutex.Unlock()
+		return nil, ErrCameraClosed
+	}
+	res := camera.apply(camera.capture, settings)
+	camera.mutex.Unlock()
+
+	applied := CameraSettings{}
+	for _, property := range res.Properties {
+		if property.Applied {
+			applied[property.Name] = property.Actual
+		}
+	}
+	if err := camera.saveProfile(res.Device, applied); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// State returns properties reported by the device and its saved profile
+func (camera *Camera) State() (*CameraState, error) {
+	res := &CameraState{Device: camera.Device(), Properties: CameraSettings{}}
+
+	camera.mutex.Lock()
+	if camera.capture != nil {
+		res.Open = true
+		for _, property := range cameraProperties {
+			res.Properties[property.Name] = camera.capture.Get(property.Property)
+		}
+	}
+	camera.mutex.Unlock()
+
+	profile, err := camera.Profile()
+	if err != nil {
+		return nil, err
+	}
+	res.Profile = profile
+	return res, nil
+}
+
+// SetCamera changes properties of the camera used by AI process
+func (app *Application) SetCamera(settings CameraSettings) (*CameraUpdate, error) {
+	update, err := app.Camera.Set(settings)
+	if err != nil {
+		return update, err
+	}
+	app.logCameraUpdate("Camera properties changed", update)
+	return update, nil
+}
+
+// CameraState returns properties of the camera used by AI process
+func (app *Application) CameraState() (*CameraState, error) {
+	return app.Camera.State()
+}
+
+// logCameraUpdate logs result of every changed property
+func (app *Application) logCameraUpdate(message string, update *CameraUpdate) {
+	for _, property := range update.Properties {
+		if property.Applied {
+			app.Log.Info(message, "device", update.Device, "property", property.Name, "value", property.Actual)
+		} else {
+			app.Log.Warn(message+": value rejected by device", "device", update.Device, "property", property.Name,
+				"requested", property.Requested, "actual", property.Actual)
+		}
+	}
+}
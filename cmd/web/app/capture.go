@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// videoSource is a camera device used by autopilot
+const videoSource = 0
+
+// cascadePaths stores Haar cascade file of every sign class
+var cascadePaths = map[int]string{
+	StopCascade:   "stop.xml",
+	CircleCascade: "circle.xml",
+	YieldCascade:  "yield.xml",
+}
+
+// loadCascades loads classifiers of every sign class
+// Classifiers must be closed by caller
+func loadCascades() (map[int]*gocv.CascadeClassifier, error) {
+	res := make(map[int]*gocv.CascadeClassifier)
+	for cascade, path := range cascadePaths {
+		classifier := gocv.NewCascadeClassifier()
+		if !classifier.Load(path) {
+			classifier.Close()
+			closeCascades(res)
+			return nil, fmt.Errorf("cascade %s can not be loaded", path)
+		}
+		res[cascade] = &classifier
+	}
+	return res, nil
+}
+
+// closeCascades releases classifiers
+func closeCascades(cascades map[int]*gocv.CascadeClassifier) {
+	for _, classifier := range cascades {
+		classifier.Close()
+	}
+}
+
+// captureInfo describes the latest frame read by AI process
+type captureInfo struct {
+	mutex     sync.Mutex
+	lastFrame time.Time
+	width     int
+	height    int
+}
+
+// recordCapture saves size and time of the frame
+func (app *Application) recordCapture(img gocv.Mat) {
+	app.capture.mutex.Lock()
+	app.capture.lastFrame = time.Now()
+	app.capture.width = img.Cols()
+	app.capture.height = img.Rows()
+	app.capture.mutex.Unlock()
+}
+
+// lastCapture returns time and size of the latest frame
+func (app *Application) lastCapture() (time.Time, int, int) {
+	app.capture.mutex.Lock()
+	defer app.capture.mutex.Unlock()
+
+	return app.capture.lastFrame, app.capture.width, app.capture.height
+}
@@ -0,0 +1,268 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// States of capture job
+const (
+	CaptureRunning = "running"
+	CaptureDone    = "done"
+	CaptureFailed  = "failed"
+)
+
+// Limits of capture jobs
+const (
+	maxCaptureFrames = 10000
+	// captureQueue is a number of frames waiting for disk, further frames are skipped
+	captureQueue = 16
+	// captureHistory is a number of finished jobs kept for status requests
+	captureHistory = 20
+)
+
+// ErrCaptureRunning is returned when another capture job is not finished
+var ErrCaptureRunning = errors.New("another capture is running")
+
+// CaptureParams describes a capture job
+type CaptureParams struct {
+	Frames int    `json:"frames"`
+	Format string `json:"format,omitempty"`
+}
+
+// CaptureStatus is a progress of capture job
+type CaptureStatus struct {
+	ID       string     `json:"id"`
+	Dir      string     `json:"dir"`
+	Format   string     `json:"format"`
+	State    string     `json:"state"`
+	Frames   int        `json:"frames"`
+	Saved    int        `json:"saved"`
+	Skipped  int        `json:"skipped"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// capturedFrame is a raw frame with its detections waiting for disk
+type capturedFrame struct {
+	img   gocv.Mat
+	event FrameEvent
+	time  time.Time
+}
+
+// captureJob saves frames in a separate goroutine, so autopilot is not slowed down by disk
+type captureJob struct {
+	mutex  sync.Mutex
+	status CaptureStatus
+	queued int
+	frames chan capturedFrame
+}
+
+// Captures saves processed frames with their metadata to disk
+type Captures struct {
+	// Dir is a root directory, every job creates its own subdirectory
+	Dir string
+
+	mutex  sync.Mutex
+	active *captureJob
+	jobs   []*captureJob
+	wg     sync.WaitGroup
+}
+
+// NewCaptures constructs Captures, which saves frames into dir
+func NewCaptures(dir string) *Captures {
+	res := &Captures{}
+	res.Dir = dir
+	return res
+}
+
+// Validate checks capture parameters, empty format means PNG
+func (params CaptureParams) Validate() error {
+	if params.Frames < 1 || params.Frames > maxCaptureFrames {
+		return fmt.Errorf("frames must be in 1..%d", maxCaptureFrames)
+	}
+	if params.Format != "" && params.Format != FormatJPEG && params.Format != FormatPNG {
+		return errors.New("format must be jpeg or png")
+	}
+	return nil
+}
+
+// Start begins saving the next processed frames
+func (captures *Captures) Start(params CaptureParams) (*CaptureStatus, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Format == "" {
+		params.Format = FormatPNG
+	}
+
+	captures.mutex.Lock()
+	defer captures.mutex.Unlock()
+
+	if captures.active != nil {
+		return nil, ErrCaptureRunning
+	}
+
+	now := time.Now()
+	id := now.Format("20060102-150405.000")
+	dir := filepath.Join(captures.Dir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	job := &captureJob{}
+	job.frames = make(chan capturedFrame, captureQueue)
+	job.status = CaptureStatus{
+		ID:      id,
+		Dir:     dir,
+		Format:  params.Format,
+		State:   CaptureRunning,
+		Frames:  params.Frames,
+		Started: now,
+	}
+
+	captures.active = job
+	captures.jobs = append(captures.jobs, job)
+	if len(captures.jobs) > captureHistory {
+		captures.jobs = captures.jobs[len(captures.jobs)-captureHistory:]
+	}
+
+	captures.wg.Add(1)
+	go func() {
+		defer captures.wg.Done()
+		captures.write(job)
+	}()
+
+	status := job.status
+	return &status, nil
+}
+
+// offer passes frame to the active job, frame is copied only when it is needed
+func (captures *Captures) offer(img gocv.Mat, event FrameEvent) {
+	captures.mutex.Lock()
+	defer captures.mutex.Unlock()
+
+	job := captures.active
+	if job == nil {
+		return
+	}
+
+	// Only offer sends to the queue, so free space can't disappear before the send
+	if len(job.frames) == cap(job.frames) {
+		job.mutex.Lock()
+		job.status.Skipped++
+		job.mutex.Unlock()
+		return
+	}
+	job.frames <- capturedFrame{img: img.Clone(), event: event, time: time.Now()}
+	job.queued++
+
+	if job.queued >= job.status.Frames {
+		close(job.frames)
+		captures.active = nil
+	}
+}
+
+// write saves frames of the job until all of them are written or disk fails
+func (captures *Captures) write(job *captureJob) {
+	var failure error
+	for frame := range job.frames {
+		if failure == nil {
+			failure = writeFrame(job.status.Dir, job.status.Format, frame)
+			if failure == nil {
+				job.mutex.Lock()
+				job.status.Saved++
+				job.mutex.Unlock()
+			}
+		}
+		frame.img.Close()
+
+		if failure != nil {
+			// Job is detached, so autopilot stops queueing frames
+			captures.mutex.Lock()
+			if captures.active == job {
+				close(job.frames)
+				captures.active = nil
+			}
+			captures.mutex.Unlock()
+		}
+	}
+
+	finished := time.Now()
+	job.mutex.Lock()
+	job.status.Finished = &finished
+	job.status.State = CaptureDone
+	if failure != nil {
+		job.status.State = CaptureFailed
+		job.status.Error = failure.Error()
+	}
+	job.mutex.Unlock()
+}
+
+// writeFrame saves image and metadata of the frame
+func writeFrame(dir string, format string, frame capturedFrame) error {
+	name := filepath.Join(dir, fmt.Sprintf("%08d", frame.event.Frame))
+	if !gocv.IMWrite(name+string(fileExt(format)), frame.img) {
+		return errors.New("image " + name + " can not be written")
+	}
+
+	metadata := struct {
+		FrameEvent
+		Time time.Time `json:"time"`
+	}{frame.event, frame.time}
+	body, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name+".json", body, 0644)
+}
+
+// Jobs returns status of recent jobs, the latest job is the last
+func (captures *Captures) Jobs() []CaptureStatus {
+	captures.mutex.Lock()
+	defer captures.mutex.Unlock()
+
+	res := make([]CaptureStatus, 0, len(captures.jobs))
+	for _, job := range captures.jobs {
+		job.mutex.Lock()
+		res = append(res, job.status)
+		job.mutex.Unlock()
+	}
+	return res
+}
+
+// Close stops the active job and waits until queued frames are written
+func (captures *Captures) Close() {
+	captures.mutex.Lock()
+	if captures.active != nil {
+		close(captures.active.frames)
+		captures.active = nil
+	}
+	captures.mutex.Unlock()
+
+	captures.wg.Wait()
+}
+
+// StartCapture begins saving the next processed frames with their detections
+func (app *Application) StartCapture(params CaptureParams) (*CaptureStatus, error) {
+	status, err := app.Captures.Start(params)
+	if err != nil {
+		return nil, err
+	}
+	app.Log.Info("Capture started", "id", status.ID, "frames", status.Frames, "format", status.Format)
+	return status, nil
+}
+
+// CaptureJobs returns status of recent capture jobs
+func (app *Application) CaptureJobs() []CaptureStatus {
+	return app.Captures.Jobs()
+}
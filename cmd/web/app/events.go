@@ -0,0 +1,225 @@
+package app
+
+import (
+	"image"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	EventFrame    = "frame"
+	EventCommand  = "command"
+	EventMode     = "mode"
+	EventError    = "error"
+	EventWatchdog = "watchdog"
+)
+
+// subscriberBuffer is a number of events queued for a slow subscriber before dropping
+const subscriberBuffer = 64
+
+// Event is a typed message for subscribers
+type Event struct {
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// FrameEvent describes detections of a processed frame
+type FrameEvent struct {
+	Frame     uint64            `json:"frame"`
+	Seq       uint64            `json:"seq"`
+	Class     string            `json:"class"`
+	Raw       []image.Rectangle `json:"raw"`
+	Near      []image.Rectangle `json:"near"`
+	Scored    []ScoredEvent     `json:"scored"`
+	Target    *image.Rectangle  `json:"target,omitempty"`
+	Throttle  int               `json:"throttle"`
+	Steering  int               `json:"steering"`
+	Failures  int               `json:"failures"`
+	Quality   FrameQuality      `json:"quality"`
+	Scan      string            `json:"scan,omitempty"`
+	Processed time.Duration     `json:"processed_ns"`
+}
+
+// ScoredEvent is a candidate with its similarity score
+type ScoredEvent struct {
+	Rect    image.Rectangle `json:"rect"`
+	Score   float64         `json:"score"`
+	Trusted bool            `json:"trusted"`
+}
+
+// CommandEvent describes a command sent to the car
+type CommandEvent struct {
+	Command string `json:"command"`
+}
+
+// ModeEvent describes change of the state machine
+type ModeEvent struct {
+	Manual  bool   `json:"manual"`
+	Blocked bool   `json:"blocked"`
+	Cascade string `json:"cascade"`
+	Overlay int    `json:"overlay"`
+}
+
+// MessageEvent describes an error or a watchdog trip
+type MessageEvent struct {
+	Message string `json:"message"`
+}
+
+// Subscription receives events of selected types
+type Subscription struct {
+	C chan Event
+
+	mutex sync.Mutex
+	types map[string]bool
+}
+
+// SetTypes changes filter of the subscription, empty list means all events
+func (sub *Subscription) SetTypes(types []string) {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	sub.types = make(map[string]bool)
+	for _, eventType := range types {
+		if eventType != "" {
+			sub.types[eventType] = true
+		}
+	}
+}
+
+func (sub *Subscription) accepts(eventType string) bool {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	return len(sub.types) == 0 || sub.types[eventType]
+}
+
+// EventBus delivers events from AI process and commands to all subscribers
+type EventBus struct {
+	mutex       sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewEventBus constructs EventBus without subscribers
+func NewEventBus() *EventBus {
+	res := &EventBus{}
+	res.subscribers = make(map[*Subscription]struct{})
+	return res
+}
+
+// Subscribe creates subscription for event types, empty list means all events
+func (bus *EventBus) Subscribe(types []string) *Subscription {
+	sub := &Subscription{C: make(chan Event, subscriberBuffer)}
+	sub.SetTypes(types)
+
+	bus.mutex.Lock()
+	bus.subscribers[sub] = struct{}{}
+	bus.mutex.Unlock()
+
+	return sub
+}
+
+// Unsubscribe stops delivery and closes the channel of subscription
+func (bus *EventBus) Unsubscribe(sub *Subscription) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	if _, ok := bus.subscribers[sub]; ok {
+		delete(bus.subscribers, sub)
+		close(sub.C)
+	}
+}
+
+// Publish sends event to subscribers without blocking
+// Slow subscriber loses events, which do not fit into its buffer
+func (bus *EventBus) Publish(eventType string, data interface{}) {
+	event := Event{Type: eventType, Time: time.Now(), Data: data}
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	for sub := range bus.subscribers {
+		if !sub.accepts(eventType) {
+			continue
+		}
+		select {
+		case sub.C <- event:
+		default:
+			eventsDropped.Inc(eventType)
+		}
+	}
+}
+
+// publishMode sends current state of the state machine
+func (app *Application) publishMode() {
+	app.events.Publish(EventMode, ModeEvent{
+		Manual:  app.IsManual,
+		Blocked: app.IsBlocked,
+		Cascade: className(app.CascadeType),
+		Overlay: app.Overlay,
+	})
+}
+
+// frameEventOf describes detections and scores of the processed frame
+func (app *Application) frameEventOf(report *FrameReport, frameID uint64, seq uint64, processed time.Duration) FrameEvent {
+	event := FrameEvent{
+		Frame:     frameID,
+		Seq:       seq,
+		Class:     className(app.CascadeType),
+		Raw:       report.Raw,
+		Near:      report.Near,
+		Throttle:  report.Throttle,
+		Steering:  report.Steering,
+		Failures:  report.FailureCounter,
+		Quality:   report.Quality,
+		Scan:      report.Scan,
+		Processed: processed,
+	}
+	for _, scored := range report.Scored {
+		event.Scored = append(event.Scored, ScoredEvent{Rect: scored.Rect, Score: scored.Score, Trusted: scored.Trusted})
+	}
+	if report.HasTarget {
+		target := report.Target
+		event.Target = &target
+	}
+	return event
+}
+
+// eventRobot publishes every command sent to the car
+type eventRobot struct {
+	RobotAccessLayer
+	events *EventBus
+}
+
+func (robot *eventRobot) checkFailure(command string) {
+	if robot.ConsecutiveFailures() > 0 {
+		robot.events.Publish(EventError, MessageEvent{Message: "command " + command + " failed"})
+	}
+}
+
+// Turn sends steering command and publishes it
+func (robot *eventRobot) Turn(steerValue int) {
+	robot.RobotAccessLayer.Turn(steerValue)
+	command := "S" + strconv.Itoa(steerValue)
+	robot.events.Publish(EventCommand, CommandEvent{Command: command})
+	robot.checkFailure(command)
+}
+
+// DirectCommand sends command and publishes it
+func (robot *eventRobot) DirectCommand(command string) {
+	robot.RobotAccessLayer.DirectCommand(command)
+	robot.events.Publish(EventCommand, CommandEvent{Command: command})
+	robot.checkFailure(command)
+}
+
+// Halt stops the car and publishes the result
+func (robot *eventRobot) Halt(attempts int) error {
+	err := robot.RobotAccessLayer.Halt(attempts)
+	robot.events.Publish(EventCommand, CommandEvent{Command: "HALT"})
+	if err != nil {
+		robot.events.Publish(EventError, MessageEvent{Message: err.Error()})
+	}
+	return err
+}
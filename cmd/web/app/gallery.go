@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gocv.io/x/gocv"
+)
+
+// DefaultGalleryPaths returns directories with reference views of every sign
+func DefaultGalleryPaths() map[int][]string {
+	return map[int][]string{
+		StopCascade:   {"gallery/stop"},
+		CircleCascade: {"gallery/circle"},
+		YieldCascade:  {"gallery/yield"},
+	}
+}
+
+// Gallery stores reference images of every sign class
+type Gallery struct {
+	Images map[int][]gocv.Mat
+	Files  map[int][]string
+}
+
+// isImageFile checks extension of the file, case is ignored
+func isImageFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".bmp":
+		return true
+	}
+	return false
+}
+
+// expandPath returns image files located by path, which can be a file or a directory
+func expandPath(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isImageFile(entry.Name()) {
+			res = append(res, filepath.Join(path, entry.Name()))
+		}
+	}
+	sort.Strings(res)
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("gallery directory %s has no images", path)
+	}
+	return res, nil
+}
+
+// LoadGallery reads reference images for every sign class
+// Any missing or unreadable file is an error
+func LoadGallery(paths map[int][]string) (*Gallery, error) {
+	res := &Gallery{}
+	res.Images = make(map[int][]gocv.Mat)
+	res.Files = make(map[int][]string)
+
+	for cascade, classPaths := range paths {
+		for _, path := range classPaths {
+			files, err := expandPath(path)
+			if err != nil {
+				res.Close()
+				return nil, err
+			}
+
+			for _, file := range files {
+				img := gocv.IMRead(file, gocv.IMReadGrayScale)
+				if img.Empty() {
+					img.Close()
+					res.Close()
+					return nil, fmt.Errorf("reference image %s can not be read", file)
+				}
+				res.Images[cascade] = append(res.Images[cascade], img)
+				res.Files[cascade] = append(res.Files[cascade], file)
+			}
+		}
+
+		if len(res.Images[cascade]) == 0 {
+			res.Close()
+			return nil, fmt.Errorf("gallery of sign %d is empty", cascade)
+		}
+	}
+
+	return res, nil
+}
+
+// Close releases all reference images
+func (gallery *Gallery) Close() {
+	for _, images := range gallery.Images {
+		for _, img := range images {
+			img.Close()
+		}
+	}
+	gallery.Images = make(map[int][]gocv.Mat)
+	gallery.Files = make(map[int][]string)
+}
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"gocv.io/x/gocv"
+	"gocv.io/x/gocv/contrib"
+)
+
+// Names of the supported image hashes
+const (
+	PHash              = "phash"
+	AverageHash        = "average"
+	BlockMeanHash      = "blockmean"
+	ColorMomentHash    = "colormoment"
+	MarrHildrethHash   = "marrhildreth"
+	RadialVarianceHash = "radialvariance"
+)
+
+// newImageHash returns hash algorithm by its name
+func newImageHash(name string) (contrib.ImgHashBase, error) {
+	switch name {
+	case PHash:
+		return contrib.PHash{}, nil
+	case AverageHash:
+		return contrib.AverageHash{}, nil
+	case BlockMeanHash:
+		return contrib.BlockMeanHash{Mode: contrib.BlockMeanHashModeDefault}, nil
+	case ColorMomentHash:
+		return contrib.ColorMomentHash{}, nil
+	case MarrHildrethHash:
+		return contrib.NewMarrHildrethHash(), nil
+	case RadialVarianceHash:
+		return contrib.NewRadialVarianceHash(), nil
+	}
+	return nil, errors.New("unknown image hash: " + name)
+}
+
+// HashVote describes a single hash in the ensemble
+// Candidate wins the vote if hash comparison result lies between MinScore and MaxScore
+type HashVote struct {
+	Hash     string
+	Weight   float64
+	MinScore float64
+	MaxScore float64
+}
+
+// HashParams describes the ensemble for a sign class
+type HashParams struct {
+	Votes []HashVote
+
+	// Quorum is a minimal part of total weight, which trusted candidate must collect
+	Quorum float64
+}
+
+// HashVerifier compares candidates with precomputed hashes of the reference gallery
+type HashVerifier struct {
+	Classes map[int]HashParams
+
+	hashes     map[string]contrib.ImgHashBase
+	references map[int][]map[string]gocv.Mat
+}
+
+// defaultVotes returns ensemble, where Color Moment Hash keeps the leading role
+// Bit hashes return Hamming distance, Radial Variance Hash returns correlation
+func defaultVotes(maxColorMoment float64) []HashVote {
+	return []HashVote{
+		{Hash: ColorMomentHash, Weight: 2, MinScore: 1.0, MaxScore: maxColorMoment},
+		{Hash: PHash, Weight: 1, MinScore: 0, MaxScore: 24},
+		{Hash: AverageHash, Weight: 1, MinScore: 0, MaxScore: 24},
+		{Hash: BlockMeanHash, Weight: 1, MinScore: 0, MaxScore: 96},
+		{Hash: MarrHildrethHash, Weight: 1, MinScore: 0, MaxScore: 220},
+		{Hash: RadialVarianceHash, Weight: 1, MinScore: 0.55, MaxScore: 1.0},
+	}
+}
+
+// NewHashVerifier constructs HashVerifier with default ensemble for every sign
+func NewHashVerifier() (*HashVerifier, error) {
+	res := &HashVerifier{}
+	res.Classes = make(map[int]HashParams)
+	res.hashes = make(map[string]contrib.ImgHashBase)
+	res.references = make(map[int][]map[string]gocv.Mat)
+
+	for _, name := range []string{PHash, AverageHash, BlockMeanHash, ColorMomentHash, MarrHildrethHash, RadialVarianceHash} {
+		hash, err := newImageHash(name)
+		if err != nil {
+			return nil, err
+		}
+		res.hashes[name] = hash
+	}
+
+	res.Classes[StopCascade] = HashParams{Votes: defaultVotes(100.0), Quorum: 0.5}
+	res.Classes[CircleCascade] = HashParams{Votes: defaultVotes(35.0), Quorum: 0.5}
+	res.Classes[YieldCascade] = HashParams{Votes: defaultVotes(140.0), Quorum: 0.5}
+
+	return res, nil
+}
+
+// SetReferences precomputes all hashes of the reference gallery for a sign class
+func (verifier *HashVerifier) SetReferences(cascade int, references []gocv.Mat) error {
+	if len(references) == 0 {
+		return fmt.Errorf("gallery of sign %d is empty", cascade)
+	}
+
+	var gallery []map[string]gocv.Mat
+	for i, reference := range references {
+		if reference.Empty() {
+			closeHashes(gallery)
+			return fmt.Errorf("reference image %d of sign %d is empty", i, cascade)
+		}
+
+		computed := make(map[string]gocv.Mat)
+		for name, hash := range verifier.hashes {
+			mat := gocv.NewMat()
+			hash.Compute(reference, &mat)
+			computed[name] = mat
+		}
+		gallery = append(gallery, computed)
+	}
+
+	closeHashes(verifier.references[cascade])
+	verifier.references[cascade] = gallery
+	return nil
+}
+
+// Verify votes for the candidate region against every reference of the gallery
+// Returns the best collected part of total weight
+func (verifier *HashVerifier) Verify(frame gocv.Mat, rect image.Rectangle, cascade int) (float64, bool) {
+	params, ok := verifier.Classes[cascade]
+	gallery := verifier.references[cascade]
+	if !ok || len(gallery) == 0 {
+		return 0, false
+	}
+
+	region := frame.Region(rect)
+	defer region.Close()
+
+	// Candidate hashes are computed once for all references
+	computed := make(map[string]gocv.Mat)
+	defer closeHashes([]map[string]gocv.Mat{computed})
+
+	totalWeight := 0.0
+	for _, vote := range params.Votes {
+		hash, ok := verifier.hashes[vote.Hash]
+		if !ok {
+			continue
+		}
+		totalWeight += vote.Weight
+
+		if _, done := computed[vote.Hash]; !done {
+			mat := gocv.NewMat()
+			hash.Compute(region, &mat)
+			computed[vote.Hash] = mat
+		}
+	}
+
+	if totalWeight == 0 {
+		return 0, false
+	}
+
+	bestRate := 0.0
+	for _, reference := range gallery {
+		votedWeight := 0.0
+		for _, vote := range params.Votes {
+			hash, ok := verifier.hashes[vote.Hash]
+			if !ok {
+				continue
+			}
+
+			score := hash.Compare(computed[vote.Hash], reference[vote.Hash])
+			if score >= vote.MinScore && score <= vote.MaxScore {
+				votedWeight += vote.Weight
+			}
+		}
+
+		rate := votedWeight / totalWeight
+		if rate > bestRate {
+			bestRate = rate
+		}
+	}
+
+	return bestRate, bestRate >= params.Quorum
+}
+
+// closeHashes releases precomputed hashes
+func closeHashes(gallery []map[string]gocv.Mat) {
+	for _, computed := range gallery {
+		for _, mat := range computed {
+			mat.Close()
+		}
+	}
+}
+
+// Close releases all precomputed hashes
+func (verifier *HashVerifier) Close() {
+	for cascade, gallery := range verifier.references {
+		closeHashes(gallery)
+		delete(verifier.references, cascade)
+	}
+}
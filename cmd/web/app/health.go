@@ -0,0 +1,153 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+// MaxIterationAge is a maximal pause of the perception loop, longer pause means it is wedged
+const MaxIterationAge = 5 * time.Second
+
+// MaxRobotFailures is a number of failed requests in a row, after which car is not ready
+const MaxRobotFailures = 3
+
+// loopMeter measures iterations of the perception loop
+// Processed frames and idle passes in manual or blocked mode are counted separately,
+// so the rate of frames is not hidden by fast idle spins
+type loopMeter struct {
+	mutex         sync.Mutex
+	lastIteration time.Time
+	windowStart   time.Time
+	windowFrames  int
+	windowIdle    int
+	frameRate     float64
+	idleRate      float64
+}
+
+// tick records a single iteration, rates are refreshed every second
+// processed is true, if the frame went through the autopilot
+func (meter *loopMeter) tick(processed bool) {
+	meter.mutex.Lock()
+	defer meter.mutex.Unlock()
+
+	now := time.Now()
+	meter.lastIteration = now
+	if processed {
+		meter.windowFrames++
+	} else {
+		meter.windowIdle++
+	}
+
+	if meter.windowStart.IsZero() {
+		meter.windowStart = now
+	}
+	if elapsed := now.Sub(meter.windowStart).Seconds(); elapsed >= 1 {
+		meter.frameRate = float64(meter.windowFrames) / elapsed
+		meter.idleRate = float64(meter.windowIdle) / elapsed
+		meter.windowStart = now
+		meter.windowFrames = 0
+		meter.windowIdle = 0
+	}
+}
+
+// snapshot returns time of the last iteration, rates of processed frames and of idle passes
+func (meter *loopMeter) snapshot() (time.Time, float64, float64) {
+	meter.mutex.Lock()
+	defer meter.mutex.Unlock()
+
+	return meter.lastIteration, meter.frameRate, meter.idleRate
+}
+
+// ComponentHealth describes state of a single subsystem
+// Live is false if subsystem is wedged, Ready is false if it can not drive the car now
+type ComponentHealth struct {
+	Name   string                 `json:"name"`
+	Live   bool                   `json:"live"`
+	Ready  bool                   `json:"ready"`
+	Detail string                 `json:"detail,omitempty"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// HealthReport collects health of every subsystem
+type HealthReport struct {
+	Live       bool              `json:"live"`
+	Ready      bool              `json:"ready"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// ageSeconds returns seconds since t, or -1 if t is zero
+func ageSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return -1
+	}
+	return time.Since(t).Seconds()
+}
+
+// Health checks video source, perception loop, robot access layer and state machine
+func (app *Application) Health() *HealthReport {
+	report := &HealthReport{Live: true, Ready: true}
+
+	// Video source
+	lastFrame, width, height := app.lastCapture()
+	video := ComponentHealth{Name: "video", Live: true, Ready: true}
+	video.Values = map[string]interface{}{
+		"last_frame_age_seconds": ageSeconds(lastFrame),
+		"width":                  width,
+		"height":                 height,
+	}
+	if lastFrame.IsZero() || time.Since(lastFrame) > app.Preflight.MaxFrameAge {
+		video.Ready = false
+		video.Detail = "no fresh frames"
+	}
+
+	// Perception loop
+	lastIteration, frameRate, idleRate := app.loop.snapshot()
+	perception := ComponentHealth{Name: "perception", Live: true, Ready: true}
+	perception.Values = map[string]interface{}{
+		"running":                    app.isRunning(),
+		"frames_per_second":          frameRate,
+		"idle_per_second":            idleRate,
+		"last_iteration_age_seconds": ageSeconds(lastIteration),
+	}
+	if !app.isRunning() {
+		perception.Live = false
+		perception.Ready = false
+		perception.Detail = "AI process is not running"
+	} else if !lastIteration.IsZero() && time.Since(lastIteration) > MaxIterationAge {
+		perception.Live = false
+		perception.Ready = false
+		perception.Detail = "perception loop is wedged"
+	}
+
+	// Robot access layer
+	lastSuccess := app.Robot.LastSuccess()
+	failures := app.Robot.ConsecutiveFailures()
+	robot := ComponentHealth{Name: "ral", Live: true, Ready: failures < MaxRobotFailures}
+	robot.Values = map[string]interface{}{
+		"last_success_age_seconds": ageSeconds(lastSuccess),
+		"consecutive_failures":     failures,
+	}
+	if !robot.Ready {
+		robot.Detail = "car does not answer"
+	}
+
+	// State machine
+	state := ComponentHealth{Name: "state", Live: true, Ready: true}
+	state.Values = map[string]interface{}{
+		"mode":        app.modeName(),
+		"cascade":     className(app.CascadeType),
+		"acquisition": app.Acquisition.Status().State,
+	}
+	if preflight := app.lastPreflight(); preflight != nil && !preflight.Passed {
+		state.Ready = false
+		state.Detail = "preflight failed"
+	}
+
+	report.Components = []ComponentHealth{video, perception, robot, state}
+	for _, component := range report.Components {
+		report.Live = report.Live && component.Live
+		report.Ready = report.Ready && component.Ready
+	}
+
+	return report
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"sync"
+)
+
+// Limits of masks
+const (
+	maxZones        = 16
+	maxZoneVertices = 64
+)
+
+// NormPoint is a point in normalised frame coordinates, 0..1 from top left corner
+type NormPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Zone is a named polygon in normalised frame coordinates
+type Zone struct {
+	Name    string      `json:"name,omitempty"`
+	Polygon []NormPoint `json:"polygon"`
+}
+
+// MaskParams restricts where signs are searched
+// Detection runs only inside include zones, empty list means the whole frame
+// Candidates with centroid inside exclude zones are rejected, e.g. bodywork of the car or ceiling lights
+type MaskParams struct {
+	Include []Zone `json:"include"`
+	Exclude []Zone `json:"exclude"`
+}
+
+// Validate checks number and coordinates of vertices
+func (params MaskParams) Validate() error {
+	if len(params.Include)+len(params.Exclude) > maxZones {
+		return fmt.Errorf("no more than %d zones are allowed", maxZones)
+	}
+	for i, zone := range append(append([]Zone{}, params.Include...), params.Exclude...) {
+		name := zone.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if len(zone.Polygon) < 3 || len(zone.Polygon) > maxZoneVertices {
+			return fmt.Errorf("zone %s must have 3..%d vertices", name, maxZoneVertices)
+		}
+		for _, point := range zone.Polygon {
+			if point.X < 0 || point.X > 1 || point.Y < 0 || point.Y > 1 {
+				return fmt.Errorf("zone %s: coordinates must be in 0..1", name)
+			}
+		}
+	}
+	return nil
+}
+
+// contains checks if point is inside polygon by ray casting
+func (zone Zone) contains(point NormPoint) bool {
+	res := false
+	polygon := zone.Polygon
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			res = !res
+		}
+	}
+	return res
+}
+
+// pixels converts polygon to pixels of the frame
+func (zone Zone) pixels(cols int, rows int) []image.Point {
+	res := make([]image.Point, 0, len(zone.Polygon))
+	for _, point := range zone.Polygon {
+		res = append(res, image.Pt(int(point.X*float64(cols)), int(point.Y*float64(rows))))
+	}
+	return res
+}
+
+// zonePixels converts polygons of zones to pixels of the frame
+func zonePixels(zones []Zone, cols int, rows int) [][]image.Point {
+	var res [][]image.Point
+	for _, zone := range zones {
+		res = append(res, zone.pixels(cols, rows))
+	}
+	return res
+}
+
+// Allows checks centroid of the candidate against include and exclude zones
+func (params MaskParams) Allows(rect image.Rectangle, cols int, rows int) bool {
+	if cols == 0 || rows == 0 {
+		return true
+	}
+	center := centerOf(rect)
+	point := NormPoint{X: float64(center.X) / float64(cols), Y: float64(center.Y) / float64(rows)}
+
+	for _, zone := range params.Exclude {
+		if zone.contains(point) {
+			return false
+		}
+	}
+	if len(params.Include) == 0 {
+		return true
+	}
+	for _, zone := range params.Include {
+		if zone.contains(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// Masks stores zones, they can be changed at any time
+type Masks struct {
+	mutex  sync.Mutex
+	params MaskParams
+}
+
+// NewMasks constructs Masks without zones
+func NewMasks() *Masks {
+	res := &Masks{}
+	res.params = MaskParams{Include: []Zone{}, Exclude: []Zone{}}
+	return res
+}
+
+// Params returns current zones
+func (masks *Masks) Params() MaskParams {
+	masks.mutex.Lock()
+	defer masks.mutex.Unlock()
+
+	return masks.params
+}
+
+// SetParams replaces all zones, they are applied from the next frame
+func (masks *Masks) SetParams(params MaskParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if params.Include == nil {
+		params.Include = []Zone{}
+	}
+	if params.Exclude == nil {
+		params.Exclude = []Zone{}
+	}
+
+	masks.mutex.Lock()
+	masks.params = params
+	masks.mutex.Unlock()
+	return nil
+}
+
+// SetMasks replaces include and exclude zones of the autopilot
+func (app *Application) SetMasks(params MaskParams) error {
+	if err := app.Masks.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Masks changed", "include", len(params.Include), "exclude", len(params.Exclude))
+	return nil
+}
+
+// MaskZones returns include and exclude zones of the autopilot
+func (app *Application) MaskZones() MaskParams {
+	return app.Masks.Params()
+}
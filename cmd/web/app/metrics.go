@@ -0,0 +1,74 @@
+package app
+
+import (
+	"github.com/RadiumByte/Robot-Server/cmd/web/metrics"
+)
+
+// Perception and control metrics
+var (
+	framesProcessed = metrics.NewCounter("robot_frames_processed_total",
+		"Frames processed by the autopilot.", "")
+	framesDropped = metrics.NewCounter("robot_frames_dropped_total",
+		"Frames dropped by buffer eraser or read empty.", "")
+	framesSkipped = metrics.NewCounter("robot_frames_skipped_total",
+		"Frames skipped by quality gate per reason.", "reason")
+	qualityGauge = metrics.NewGauge("robot_frame_quality",
+		"Quality measures of the last frame.", "measure")
+	stageLatency = metrics.NewHistogram("robot_stage_latency_seconds",
+		"Latency of the autopilot stages.", "stage", metrics.DefaultBuckets)
+	detectionsTotal = metrics.NewCounter("robot_detections_total",
+		"Cascade detections after grouping per sign class.", "class")
+	rejectionsTotal = metrics.NewCounter("robot_rejections_total",
+		"Candidates rejected per filter stage.", "stage")
+	searchScans = metrics.NewCounter("robot_search_scans_total",
+		"Detection scans per kind, fallback is a full scan after the window missed.", "scan")
+	searchFPS = metrics.NewGauge("robot_search_fps",
+		"Smoothed detection rate of a single scan per kind.", "scan")
+	searchGain = metrics.NewGauge("robot_search_gain",
+		"Ratio of window scan rate to full scan rate.", "")
+	eventsDropped = metrics.NewCounter("robot_events_dropped_total",
+		"Events dropped for slow subscribers per type.", "type")
+	modeGauge = metrics.NewGauge("robot_mode",
+		"Current driving mode, 1 for the active one.", "mode")
+)
+
+// Latency stages
+const (
+	latencyCapture    = "capture"
+	latencyPreprocess = "preprocess"
+	latencyQuality    = "quality"
+	latencyDetect     = "detect"
+	latencyFilter     = "filter"
+	latencyCommand    = "command"
+)
+
+// className returns readable name of the cascade type
+func className(cascade int) string {
+	switch cascade {
+	case StopCascade:
+		return "stop"
+	case CircleCascade:
+		return "circle"
+	case YieldCascade:
+		return "yield"
+	}
+	return "unknown"
+}
+
+// updateModeGauge exports current driving mode
+func (app *Application) updateModeGauge() {
+	current := "auto"
+	if app.IsManual {
+		current = "manual"
+	} else if app.IsBlocked {
+		current = "blocked"
+	}
+
+	for _, mode := range []string{"auto", "manual", "blocked"} {
+		value := 0.0
+		if mode == current {
+			value = 1.0
+		}
+		modeGauge.Set(mode, value)
+	}
+}
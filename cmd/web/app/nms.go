@@ -0,0 +1,201 @@
+package app
+
+import (
+	"errors"
+	"image"
+	"math"
+	"sort"
+	"sync"
+
+	"gocv.io/x/gocv"
+)
+
+// GroupingParams configures merging of overlapping cascade hits
+type GroupingParams struct {
+	Enabled bool `json:"enabled"`
+
+	// UseGroupRectangles switches from NMS to OpenCV rectangles clustering
+	UseGroupRectangles bool `json:"use_group_rectangles"`
+
+	// GroupThreshold is a minimal number of hits of one sign, 1 keeps isolated hits
+	GroupThreshold int `json:"group_threshold"`
+
+	// GroupEps is a relative difference of sides of boxes, which belong to one sign
+	GroupEps float64 `json:"group_eps"`
+
+	// IoUThreshold is a minimal intersection over union of boxes, which belong to one sign
+	IoUThreshold float64 `json:"iou_threshold"`
+
+	// MergeBoxes averages boxes of one sign weighted by their score
+	// Otherwise only the box with best score is kept
+	MergeBoxes bool `json:"merge_boxes"`
+}
+
+// DefaultGroupingParams returns params of NMS with box merging
+func DefaultGroupingParams() GroupingParams {
+	return GroupingParams{
+		Enabled:        true,
+		GroupThreshold: 1,
+		GroupEps:       0.2,
+		IoUThreshold:   0.3,
+		MergeBoxes:     true,
+	}
+}
+
+// Validate checks grouping params
+func (params GroupingParams) Validate() error {
+	if params.GroupThreshold < 1 || params.GroupThreshold > 10 {
+		return errors.New("group_threshold must be in 1..10")
+	}
+	if params.GroupEps <= 0 || params.GroupEps > 2 {
+		return errors.New("group_eps must be in 0..2")
+	}
+	if params.IoUThreshold <= 0 || params.IoUThreshold >= 1 {
+		return errors.New("iou_threshold must be in 0..1")
+	}
+	return nil
+}
+
+// Grouping stores params of hits grouping, they can be changed at any time
+type Grouping struct {
+	mutex  sync.Mutex
+	params GroupingParams
+}
+
+// NewGrouping constructs Grouping with default params
+func NewGrouping() *Grouping {
+	res := &Grouping{}
+	res.params = DefaultGroupingParams()
+	return res
+}
+
+// Params returns current params
+func (grouping *Grouping) Params() GroupingParams {
+	grouping.mutex.Lock()
+	defer grouping.mutex.Unlock()
+
+	return grouping.params
+}
+
+// SetParams changes params, they are applied from the next frame
+func (grouping *Grouping) SetParams(params GroupingParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	grouping.mutex.Lock()
+	grouping.params = params
+	grouping.mutex.Unlock()
+	return nil
+}
+
+// SetGrouping changes grouping of cascade hits
+func (app *Application) SetGrouping(params GroupingParams) error {
+	if err := app.Grouping.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Grouping changed", "enabled", params.Enabled, "use_group_rectangles", params.UseGroupRectangles,
+		"group_threshold", params.GroupThreshold, "group_eps", params.GroupEps,
+		"iou_threshold", params.IoUThreshold, "merge_boxes", params.MergeBoxes)
+	return nil
+}
+
+// GroupingSettings returns params of hits grouping
+func (app *Application) GroupingSettings() GroupingParams {
+	return app.Grouping.Params()
+}
+
+// intersectionOverUnion calculates overlapping rate of two boxes
+func intersectionOverUnion(a image.Rectangle, b image.Rectangle) float64 {
+	intersection := a.Intersect(b)
+	if intersection.Empty() {
+		return 0
+	}
+
+	intersectionSquare := float64(intersection.Dx() * intersection.Dy())
+	unionSquare := float64(a.Dx()*a.Dy()+b.Dx()*b.Dy()) - intersectionSquare
+	if unionSquare <= 0 {
+		return 0
+	}
+	return intersectionSquare / unionSquare
+}
+
+// GroupDetections leaves only one box for every physical sign
+func GroupDetections(rects []image.Rectangle, params GroupingParams) []image.Rectangle {
+	if !params.Enabled || len(rects) < 2 {
+		return rects
+	}
+
+	if params.UseGroupRectangles {
+		// OpenCV keeps clusters of more than threshold boxes, so every box is doubled
+		// to keep signs with GroupThreshold hits, including isolated ones
+		doubled := append(append([]image.Rectangle{}, rects...), rects...)
+		return gocv.GroupRectangles(doubled, 2*params.GroupThreshold-1, params.GroupEps)
+	}
+	return suppressNonMaximum(rects, params)
+}
+
+// suppressNonMaximum clusters boxes around the biggest ones
+// Square of the box is its score, because cascade does not return confidence
+func suppressNonMaximum(rects []image.Rectangle, params GroupingParams) []image.Rectangle {
+	order := make([]int, len(rects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a := rects[order[i]]
+		b := rects[order[j]]
+		return a.Dx()*a.Dy() > b.Dx()*b.Dy()
+	})
+
+	suppressed := make([]bool, len(rects))
+	var res []image.Rectangle
+
+	for _, i := range order {
+		if suppressed[i] {
+			continue
+		}
+
+		var cluster []image.Rectangle
+		for _, j := range order {
+			if suppressed[j] {
+				continue
+			}
+			if i == j || intersectionOverUnion(rects[i], rects[j]) > params.IoUThreshold {
+				suppressed[j] = true
+				cluster = append(cluster, rects[j])
+			}
+		}
+
+		if params.MergeBoxes {
+			res = append(res, mergeBoxes(cluster))
+		} else {
+			res = append(res, rects[i])
+		}
+	}
+
+	return res
+}
+
+// mergeBoxes averages coordinates of boxes weighted by their square
+func mergeBoxes(cluster []image.Rectangle) image.Rectangle {
+	var minX, minY, maxX, maxY, totalScore float64
+	for _, rect := range cluster {
+		score := float64(rect.Dx() * rect.Dy())
+		minX += float64(rect.Min.X) * score
+		minY += float64(rect.Min.Y) * score
+		maxX += float64(rect.Max.X) * score
+		maxY += float64(rect.Max.Y) * score
+		totalScore += score
+	}
+
+	if totalScore == 0 {
+		return cluster[0]
+	}
+
+	return image.Rect(
+		int(math.Round(minX/totalScore)),
+		int(math.Round(minY/totalScore)),
+		int(math.Round(maxX/totalScore)),
+		int(math.Round(maxY/totalScore)))
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"image"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestIntersectionOverUnion(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b image.Rectangle
+		iou  float64
+	}{
+		{"same", image.Rect(0, 0, 10, 10), image.Rect(0, 0, 10, 10), 1},
+		{"half shifted", image.Rect(0, 0, 10, 10), image.Rect(5, 0, 15, 10), 1.0 / 3},
+		{"inside", image.Rect(0, 0, 10, 10), image.Rect(0, 0, 20, 20), 0.25},
+		{"touching", image.Rect(0, 0, 10, 10), image.Rect(10, 0, 20, 10), 0},
+		{"disjoint", image.Rect(0, 0, 10, 10), image.Rect(50, 50, 60, 60), 0},
+		{"empty", image.Rect(0, 0, 10, 10), image.Rectangle{}, 0},
+	}
+
+	for _, test := range tests {
+		if iou := intersectionOverUnion(test.a, test.b); math.Abs(iou-test.iou) > 1e-9 {
+			t.Errorf("%s: iou %v, expected %v", test.name, iou, test.iou)
+		}
+		if iou := intersectionOverUnion(test.b, test.a); math.Abs(iou-test.iou) > 1e-9 {
+			t.Errorf("%s: iou is not symmetric: %v, expected %v", test.name, iou, test.iou)
+		}
+	}
+}
+
+func TestSuppressNonMaximum(t *testing.T) {
+	keepBest := DefaultGroupingParams()
+	keepBest.MergeBoxes = false
+	merge := DefaultGroupingParams()
+	strict := DefaultGroupingParams()
+	strict.MergeBoxes = false
+	strict.IoUThreshold = 0.8
+
+	tests := []struct {
+		name   string
+		rects  []image.Rectangle
+		params GroupingParams
+		res    []image.Rectangle
+	}{
+		{
+			name:   "overlapping boxes keep the biggest",
+			rects:  []image.Rectangle{image.Rect(2, 0, 11, 10), image.Rect(0, 0, 10, 10)},
+			params: keepBest,
+			res:    []image.Rectangle{image.Rect(0, 0, 10, 10)},
+		},
+		{
+			name:   "overlapping boxes are averaged",
+			rects:  []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(2, 0, 12, 10)},
+			params: merge,
+			res:    []image.Rectangle{image.Rect(1, 0, 11, 10)},
+		},
+		{
+			name:   "separate signs are kept, biggest first",
+			rects:  []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(50, 50, 70, 70)},
+			params: merge,
+			res:    []image.Rectangle{image.Rect(50, 50, 70, 70), image.Rect(0, 0, 10, 10)},
+		},
+		{
+			name:   "overlap below threshold is kept",
+			rects:  []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(2, 0, 11, 10)},
+			params: strict,
+			res:    []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(2, 0, 11, 10)},
+		},
+		{
+			name: "cluster does not swallow the next sign",
+			rects: []image.Rectangle{
+				image.Rect(0, 0, 20, 20), image.Rect(1, 1, 20, 20),
+				image.Rect(100, 0, 110, 10), image.Rect(101, 0, 110, 10),
+			},
+			params: keepBest,
+			res:    []image.Rectangle{image.Rect(0, 0, 20, 20), image.Rect(100, 0, 110, 10)},
+		},
+	}
+
+	for _, test := range tests {
+		if res := suppressNonMaximum(test.rects, test.params); !reflect.DeepEqual(res, test.res) {
+			t.Errorf("%s: %v, expected %v", test.name, res, test.res)
+		}
+	}
+}
+
+func TestGroupingParamsValidate(t *testing.T) {
+	if err := DefaultGroupingParams().Validate(); err != nil {
+		t.Fatalf("default params are invalid: %v", err)
+	}
+
+	params := DefaultGroupingParams()
+	params.GroupThreshold = 0
+	if params.Validate() == nil {
+		t.Error("group_threshold 0 is accepted")
+	}
+
+	params = DefaultGroupingParams()
+	params.IoUThreshold = 1
+	if params.Validate() == nil {
+		t.Error("iou_threshold 1 is accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"gocv.io/x/gocv"
+)
+
+// Overlay modes
+// 0 - only the final target
+// 1 - every stage of the filter funnel with HUD
+const (
+	OverlayTarget = 0
+	OverlayFunnel = 1
+)
+
+// Colors of the overlay
+var (
+	// Color of bounding box around the target
+	blue   = color.RGBA{0, 0, 255, 0}
+	gray   = color.RGBA{160, 160, 160, 0}
+	yellow = color.RGBA{255, 255, 0, 0}
+	green  = color.RGBA{0, 255, 0, 0}
+	red    = color.RGBA{255, 0, 0, 0}
+	white  = color.RGBA{255, 255, 255, 0}
+	black  = color.RGBA{0, 0, 0, 0}
+)
+
+// ScoredRect is a candidate, which was compared with the reference gallery
+type ScoredRect struct {
+	Rect    image.Rectangle
+	Score   float64
+	Trusted bool
+}
+
+// FrameReport describes how a single frame passed the filter funnel
+type FrameReport struct {
+	// Raw stores cascade hits after grouping
+	Raw []image.Rectangle
+
+	// Near stores candidates, which passed the geometric gate
+	Near []image.Rectangle
+
+	// Scored stores candidates, which were checked by image hashes
+	Scored []ScoredRect
+
+	Target    image.Rectangle
+	HasTarget bool
+
+	// Preprocess describes steps applied before detection
+	Preprocess string
+	// ROI is a part of the frame searched by cascade, zero value means the whole frame
+	ROI image.Rectangle
+	// Masks are zones applied to the frame
+	Masks MaskParams
+	// Scan is a kind of the detection scan, empty when frame was skipped
+	Scan string
+	// Quality is a result of the quality gate, detection is skipped on unusable frames
+	Quality FrameQuality
+
+	Throttle       int
+	Steering       int
+	FailureCounter int
+}
+
+// HUD stores values printed at the top of the frame
+type HUD struct {
+	Mode string
+	FPS  float64
+}
+
+// drawLabel puts text centered above the box
+func drawLabel(img *gocv.Mat, rect image.Rectangle, text string, c color.RGBA) {
+	size := gocv.GetTextSize(text, gocv.FontHersheyPlain, 1.2, 2)
+	pt := image.Pt(rect.Min.X+(rect.Dx()/2)-(size.X/2), rect.Min.Y-2)
+	gocv.PutText(img, text, pt, gocv.FontHersheyPlain, 1.2, c, 2)
+}
+
+// drawOverlay draws report of the frame according to the overlay mode
+func drawOverlay(img *gocv.Mat, report *FrameReport, mode int, hud HUD) {
+	if report == nil {
+		return
+	}
+
+	if mode == OverlayFunnel {
+		if !report.ROI.Empty() {
+			gocv.Rectangle(img, report.ROI, white, 1)
+		}
+		if len(report.Masks.Include) > 0 {
+			gocv.DrawContours(img, zonePixels(report.Masks.Include, img.Cols(), img.Rows()), -1, green, 1)
+		}
+		if len(report.Masks.Exclude) > 0 {
+			gocv.DrawContours(img, zonePixels(report.Masks.Exclude, img.Cols(), img.Rows()), -1, red, 1)
+		}
+		for _, rect := range report.Raw {
+			gocv.Rectangle(img, rect, gray, 1)
+		}
+		for _, rect := range report.Near {
+			gocv.Rectangle(img, rect, yellow, 2)
+		}
+		for _, scored := range report.Scored {
+			c := red
+			if scored.Trusted {
+				c = green
+			}
+			gocv.Rectangle(img, scored.Rect, c, 2)
+			gocv.PutText(img, fmt.Sprintf("%0.2f", scored.Score),
+				image.Pt(scored.Rect.Max.X+4, scored.Rect.Min.Y+12), gocv.FontHersheyPlain, 1.0, c, 1)
+		}
+	}
+
+	if report.HasTarget {
+		gocv.Rectangle(img, report.Target, blue, 3)
+		drawLabel(img, report.Target, "Target", blue)
+	}
+
+	if mode == OverlayFunnel {
+		lines := []string{
+			fmt.Sprintf("Mode: %s", hud.Mode),
+			fmt.Sprintf("FPS: %0.1f", hud.FPS),
+			fmt.Sprintf("Throttle: %d Steering: %d", report.Throttle, report.Steering),
+			fmt.Sprintf("Failures: %d", report.FailureCounter),
+			fmt.Sprintf("Preprocess: %s", report.Preprocess),
+			fmt.Sprintf("Quality: %s", report.Quality),
+			fmt.Sprintf("Scan: %s", report.Scan),
+		}
+		for i, line := range lines {
+			gocv.PutText(img, line, image.Pt(8, 18+i*18), gocv.FontHersheyPlain, 1.1, white, 1)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package app
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
+	"gocv.io/x/gocv"
+)
+
+// autopilot stores "memory" of the driving loop between frames
+type autopilot struct {
+	app      *Application
+	log      *logger.Entry
+	cascades map[int]*gocv.CascadeClassifier
+
+	// "Memory" about previous correct target
+	// It is a component of noise supperssion
+	prevTargetCenter image.Point
+	prevTargetSquare float64
+
+	// Search window is placed by the previous target and its shift between frames
+	prevTarget         image.Rectangle
+	prevTargetVelocity image.Point
+	// Number of window scans since the last full scan
+	windowScans int
+	search      searchMeter
+
+	// Preprocessing time of the current frame, it is spent in undistortion and in every scan
+	preprocessTime time.Duration
+
+	// Target detection on the first step varies from others
+	isFirstIteration bool
+
+	// Car behaviour configuration
+	// Change car's speed while driving forward
+	maxThrottle int
+	minThrottle int
+
+	// Change distances for min and max speed
+	// Min speed:
+	maxSquare float64
+	// Max speed:
+	minSquare float64
+
+	// Memory about last car's movement
+	// Need it for reducing network load
+	prevThrottle int
+	prevSteering int
+
+	// Last sent throttle, negative value means driving backward
+	throttle int
+
+	// How fast car will accelerate backward
+	backwardAcceleration float64
+
+	// Max speed for driving backward
+	maxBackwardThrottle int
+
+	// This counter checks how many times cascade returned empty data
+	failureCounter int
+
+	// Start of the series of unusable frames, zero when the last frame was usable
+	unusableSince time.Time
+	// Car was halted, because frames were unusable longer than allowed
+	isHoldExpired bool
+}
+
+// newAutopilot constructs autopilot with default car behaviour
+func newAutopilot(app *Application, log *logger.Entry, cascades map[int]*gocv.CascadeClassifier) *autopilot {
+	res := &autopilot{}
+	res.app = app
+	res.log = log
+	res.cascades = cascades
+	res.isFirstIteration = true
+
+	res.maxThrottle = 70
+	res.minThrottle = 15
+	res.maxSquare = 85 * 85
+	res.minSquare = 35 * 35
+
+	res.prevThrottle = 0
+	res.prevSteering = 50
+
+	res.backwardAcceleration = 650.0
+	res.maxBackwardThrottle = 60
+
+	return res
+}
+
+// process runs the filter funnel on a single frame and drives the car
+// Frame and sequence IDs are attached to every log record of the frame
+func (pilot *autopilot) process(imgCurrent gocv.Mat, frameID uint64, seq uint64) *FrameReport {
+	app := pilot.app
+	log := pilot.log.With("frame", frameID, "seq", seq)
+	report := &FrameReport{}
+	defer func() {
+		report.Throttle = pilot.throttle
+		report.Steering = pilot.prevSteering
+		report.FailureCounter = pilot.failureCounter
+	}()
+
+	// Constants for object filtering
+	// They are choosing automatically, don't set them here
+	var MAX_DISTANCE_DIFF float64
+	var MAX_SQUARE_DIFF float64
+
+	// Blurred or badly exposed frame can't confirm or lose the target, so the last command is held
+	quality := app.Quality.Params()
+	qualityStart := time.Now()
+	report.Quality = app.Quality.Assess(imgCurrent, quality)
+	stageLatency.Since(latencyQuality, qualityStart)
+	if !report.Quality.Usable {
+		pilot.hold(report.Quality, quality.holdDuration(), log)
+		return report
+	}
+	pilot.unusableSince = time.Time{}
+	pilot.isHoldExpired = false
+
+	// Steps are fixed for the whole frame, even if they are changed meanwhile
+	preprocess := app.Preprocess.Params()
+	preprocessStart := time.Now()
+	app.Preprocess.Undistort(&imgCurrent, preprocess)
+	pilot.preprocessTime = time.Since(preprocessStart)
+	masks := app.Masks.Params()
+	cols, rows := imgCurrent.Cols(), imgCurrent.Rows()
+	include := zonePixels(masks.Include, cols, rows)
+
+	// Tracked target is searched near its predicted position, the whole frame is scanned regularly
+	// and after every frame without trusted target
+	search := app.Search.Params()
+	scan := ScanFull
+	var window searchWindow
+	if search.Enabled && !pilot.isFirstIteration && pilot.failureCounter == 0 &&
+		pilot.windowScans < search.FullScanEvery {
+		window = search.window(pilot.prevTarget, pilot.prevTargetVelocity, cols, rows)
+		if !window.Region.Empty() {
+			scan = ScanWindow
+		}
+	}
+
+	rawObjects, view := pilot.detect(imgCurrent, preprocess, include, window)
+	if scan == ScanWindow && len(rawObjects) == 0 {
+		// Target left the window or changed its size too fast
+		scan = ScanFallback
+		rawObjects, view = pilot.detect(imgCurrent, preprocess, include, searchWindow{})
+	}
+	stageLatency.Observe(latencyPreprocess, pilot.preprocessTime.Seconds())
+	if scan == ScanWindow {
+		pilot.windowScans++
+	} else {
+		pilot.windowScans = 0
+	}
+	searchScans.Inc(scan)
+
+	report.Scan = scan
+	report.Preprocess = preprocess.Summary()
+	report.Masks = masks
+	if view.Region != image.Rect(0, 0, cols, rows) {
+		report.ROI = view.Region
+	}
+	filterStart := time.Now()
+
+	if app.CascadeType == StopCascade {
+		MAX_DISTANCE_DIFF = 400.0
+		MAX_SQUARE_DIFF = 40000.0
+
+	} else if app.CascadeType == CircleCascade {
+		MAX_DISTANCE_DIFF = 200.0
+		MAX_SQUARE_DIFF = 20000.0
+
+	} else if app.CascadeType == YieldCascade {
+		MAX_DISTANCE_DIFF = 600.0
+		MAX_SQUARE_DIFF = 60000.0
+	}
+
+	// Overlapping boxes of one sign are grouped, so filters see one candidate per sign
+	rawObjects = GroupDetections(rawObjects, app.Grouping.Params())
+	report.Raw = rawObjects
+	detectionsTotal.Add(className(app.CascadeType), float64(len(rawObjects)))
+
+	// Candidates with centroid in excluded zones are rejected before other filters
+	var allowedObjects []image.Rectangle
+	for _, rect := range rawObjects {
+		isAllowed := masks.Allows(rect, cols, rows)
+		app.Stats.Record(StageMask, isAllowed)
+		if isAllowed {
+			allowedObjects = append(allowedObjects, rect)
+		}
+	}
+	rawObjects = allowedObjects
+
+	if pilot.failureCounter >= 5 {
+		// All normal targets disappeared, so car halts
+		commandStart := time.Now()
+		app.Robot.DirectCommand("HALT")
+		stageLatency.Since(latencyCommand, commandStart)
+		pilot.throttle = 0
+		if !pilot.isFirstIteration {
+			app.events.Publish(EventWatchdog, MessageEvent{Message: "targets lost, car halted"})
+		}
+		pilot.isFirstIteration = true
+		app.Acquisition.Reset()
+	}
+
+	if len(rawObjects) == 0 {
+		log.Debug("Cascade returned empty result")
+		pilot.failureCounter = pilot.failureCounter + 1
+		if pilot.isFirstIteration {
+			app.Acquisition.Observe(image.Rectangle{}, false)
+		}
+		return report
+	}
+
+	// trustedObjects stores targets, which passed content and geometrical conditions
+	var trustedObjects []image.Rectangle
+
+	if pilot.isFirstIteration {
+		// Target acquisition - biggest object, which passed shape and hash verification,
+		// must persist on several frames before car moves
+		var candidate image.Rectangle
+		isVerified := false
+		maxSquare := 0.0
+		for _, rect := range rawObjects {
+			isShaped := app.Shape.Verify(imgCurrent, rect, app.CascadeType)
+			app.Stats.Record(StageShape, isShaped)
+			if !isShaped {
+				continue
+			}
+
+			similarity, isSimilar := app.Hash.Verify(imgCurrent, rect, app.CascadeType)
+			app.Stats.Record(StageHash, isSimilar)
+			report.Scored = append(report.Scored, ScoredRect{Rect: rect, Score: similarity, Trusted: isSimilar})
+			if !isSimilar {
+				continue
+			}
+
+			currentSquare := float64(rect.Dx() * rect.Dy())
+			if currentSquare > maxSquare {
+				maxSquare = currentSquare
+				candidate = rect
+				isVerified = true
+			}
+		}
+
+		if !app.Acquisition.Observe(candidate, isVerified) {
+			acquisition := app.Acquisition.Status()
+			log.Debug("Target acquisition", "state", acquisition.State,
+				"confirmed", acquisition.Confirmed, "required", acquisition.Required)
+			return report
+		}
+		// Target is locked only on a verified frame, so candidate is the box of this frame
+		trustedObjects = append(trustedObjects, candidate)
+
+	} else {
+		// Generic multi-step filtering, enables after first iteration
+		// First step - find geometrically close objects by their square and location
+
+		// nearObjects stores targets, which passed geometrical conditions
+		var nearObjects []image.Rectangle
+
+		for _, rect := range rawObjects {
+			square := rect.Dx() * rect.Dy()
+			centroidCurrent := centerOf(rect)
+
+			log.Debug("Geometric gate",
+				"distance", distBetweenPoints(centroidCurrent, pilot.prevTargetCenter),
+				"square_diff", math.Abs(float64(square)-pilot.prevTargetSquare))
+
+			isNear := distBetweenPoints(centroidCurrent, pilot.prevTargetCenter) < MAX_DISTANCE_DIFF &&
+				math.Abs(float64(square)-pilot.prevTargetSquare) < MAX_SQUARE_DIFF
+			app.Stats.Record(StageGeometry, isNear)
+
+			if isNear {
+				nearObjects = append(nearObjects, rect)
+			}
+		}
+		report.Near = nearObjects
+
+		// Second step - contour of the target must have expected shape
+		var shapedObjects []image.Rectangle
+		for _, rect := range nearObjects {
+			isShaped := app.Shape.Verify(imgCurrent, rect, app.CascadeType)
+			app.Stats.Record(StageShape, isShaped)
+
+			if isShaped {
+				shapedObjects = append(shapedObjects, rect)
+			}
+		}
+
+		// Third step - ensemble of image hashes compares target with preloaded model
+		for _, rect := range shapedObjects {
+			similarity, isSimilar := app.Hash.Verify(imgCurrent, rect, app.CascadeType)
+			log.Debug("Hash similarity", "similarity", similarity)
+
+			app.Stats.Record(StageHash, isSimilar)
+			report.Scored = append(report.Scored, ScoredRect{Rect: rect, Score: similarity, Trusted: isSimilar})
+
+			if isSimilar {
+				trustedObjects = append(trustedObjects, rect)
+			}
+		}
+	}
+
+	if len(trustedObjects) == 0 {
+		log.Debug("No trusted objects found")
+		pilot.failureCounter = pilot.failureCounter + 1
+		return report
+	}
+
+	// finalObject stores only one target, selected by filter
+	var finalObject image.Rectangle
+
+	// This situation occurs if several good targets found
+	// Need to find largest (closest) good target
+	if len(trustedObjects) > 1 {
+		maxSquareIndex := -1
+		maxSquare := 0.0
+		for i, rect := range trustedObjects {
+			currentSquare := float64(rect.Dx() * rect.Dy())
+			if currentSquare > maxSquare {
+				maxSquare = currentSquare
+				maxSquareIndex = i
+			}
+		}
+		finalObject = trustedObjects[maxSquareIndex]
+	} else if len(trustedObjects) == 1 {
+		finalObject = trustedObjects[0]
+	}
+
+	report.Target = finalObject
+	report.HasTarget = true
+
+	pilot.failureCounter = 0
+	stageLatency.Since(latencyFilter, filterStart)
+
+	commandStart := time.Now()
+	pilot.drive(finalObject, imgCurrent.Cols(), log)
+	stageLatency.Since(latencyCommand, commandStart)
+
+	// Refresh previous center and square, velocity is known only when the target was tracked
+	if pilot.isFirstIteration {
+		pilot.prevTargetVelocity = image.Point{}
+	} else {
+		pilot.prevTargetVelocity = centerOf(finalObject).Sub(pilot.prevTargetCenter)
+	}
+	pilot.prevTarget = finalObject
+	pilot.prevTargetCenter = centerOf(finalObject)
+	pilot.prevTargetSquare = float64(finalObject.Dx() * finalObject.Dy())
+
+	pilot.isFirstIteration = false
+
+	return report
+}
+
+// detect runs cascade on the frame or on the search window
+// Returned boxes are everything, which Haar Cascade found, including noise, in frame coordinates
+func (pilot *autopilot) detect(img gocv.Mat, preprocess PreprocessParams, include [][]image.Point,
+	window searchWindow) ([]image.Rectangle, detectionView) {
+	app := pilot.app
+
+	preprocessStart := time.Now()
+	prepared, view := app.Preprocess.Prepare(img, preprocess, include, window.Region)
+	defer prepared.Close()
+	pilot.preprocessTime += time.Since(preprocessStart)
+
+	cascade, ok := pilot.cascades[app.CascadeType]
+	if !ok || prepared.Empty() {
+		return nil, view
+	}
+
+	detectStart := time.Now()
+	scan := ScanFull
+	var rects []image.Rectangle
+	if window.Region.Empty() {
+		rects = cascade.DetectMultiScale(prepared)
+	} else {
+		// Sizes close to the previous target are searched, they are scaled as the prepared image
+		scan = ScanWindow
+		rects = cascade.DetectMultiScaleWithParams(prepared, cascadeScaleFactor, cascadeMinNeighbors, 0,
+			view.scaled(window.MinSize), view.scaled(window.MaxSize))
+	}
+	stageLatency.Since(latencyDetect, detectStart)
+	pilot.search.observe(scan, time.Since(detectStart))
+
+	// Boxes of preprocessed image are mapped back, so verifiers and overlay use the frame
+	return view.toFrame(rects), view
+}
+
+// hold keeps the last command while frames are unusable, car halts when MaxHold is over
+func (pilot *autopilot) hold(quality FrameQuality, maxHold time.Duration, log *logger.Entry) {
+	app := pilot.app
+	now := time.Now()
+	if pilot.unusableSince.IsZero() {
+		pilot.unusableSince = now
+	}
+	log.Debug("Frame skipped", "reason", quality.Reason, "sharpness", quality.Sharpness,
+		"brightness", quality.Brightness, "overexposed", quality.Overexposed)
+
+	if pilot.isHoldExpired || now.Sub(pilot.unusableSince) < maxHold {
+		return
+	}
+
+	commandStart := time.Now()
+	app.Robot.DirectCommand("HALT")
+	stageLatency.Since(latencyCommand, commandStart)
+	pilot.throttle = 0
+	pilot.prevThrottle = 0
+	pilot.isHoldExpired = true
+	app.events.Publish(EventWatchdog, MessageEvent{Message: "frames unusable (" + quality.Reason + "), car halted"})
+	pilot.isFirstIteration = true
+	app.Acquisition.Reset()
+}
+
+// drive sends throttle and steering commands, calculated from the target position
+func (pilot *autopilot) drive(finalObject image.Rectangle, frameWidth int, log *logger.Entry) {
+	app := pilot.app
+
+	// Car throttle logic
+	// Throttle depends on a distance to target
+	// Bigger square - lower throttle down to full stop
+
+	// Actual size of the target
+	targetSquare := float64(finalObject.Dx() * finalObject.Dy())
+	log.Debug("Target square", "square", targetSquare)
+
+	if targetSquare > pilot.maxSquare {
+		// Target is too close - car is going backward
+
+		deltaSquare := targetSquare - pilot.maxSquare
+		calculatedThrottle := int(deltaSquare / pilot.backwardAcceleration)
+
+		if calculatedThrottle > pilot.maxBackwardThrottle {
+			calculatedThrottle = pilot.maxBackwardThrottle
+		}
+
+		calculatedThrottleStr := strconv.Itoa(calculatedThrottle)
+		app.Robot.DirectCommand("B" + calculatedThrottleStr)
+		pilot.throttle = -calculatedThrottle
+	} else {
+		// Target in range - car is going forward
+
+		targetSquareInRange := pilot.maxSquare - targetSquare
+		deltaThrottle := pilot.maxThrottle - pilot.minThrottle
+		deltaSquare := math.Abs(pilot.maxSquare - pilot.minSquare)
+
+		calculatedThrottle := int(((float64(deltaThrottle) * targetSquareInRange) / deltaSquare) + float64(pilot.minThrottle))
+
+		if calculatedThrottle > pilot.maxThrottle {
+			calculatedThrottle = pilot.maxThrottle
+		}
+
+		// Throttle sensivity
+		// If throttle is almost the same as previous - no need to send command again
+		if math.Abs(float64(calculatedThrottle-pilot.prevThrottle)) > 2 {
+			pilot.prevThrottle = calculatedThrottle
+			calculatedThrottleStr := strconv.Itoa(calculatedThrottle)
+			app.Robot.DirectCommand("F" + calculatedThrottleStr)
+			pilot.throttle = calculatedThrottle
+		}
+	}
+
+	// Car steering logic
+	// Horizontal position of target influences on wheels steering
+	var command int
+
+	// Calculate center of the target
+	centroid := centerOf(finalObject)
+
+	// Borders determine tube area in the center of frame
+	// In this area car won't steer in any directions
+	rightBorder := int(float64(frameWidth) * 0.52)
+	leftBorder := int(float64(frameWidth) * 0.48)
+
+	if centroid.X >= leftBorder && centroid.X <= rightBorder {
+		// Target in tube - need to ride forward
+		command = 50
+
+	} else if centroid.X < leftBorder {
+		// Need to steer left
+		command = (50 * centroid.X) / leftBorder
+
+	} else if centroid.X > rightBorder {
+		// Need to steer right
+		command = ((50 * centroid.X) / (frameWidth - rightBorder)) - 12
+	}
+
+	// Max turning if target is going to escape frame
+	// In theory, this will increase steering ability
+	if command >= 80 {
+		command = 100
+	} else if command <= 20 {
+		command = 0
+	}
+
+	// Steering sensivity
+	// If steering is almost the same as previous - no need to send command again
+	if math.Abs(float64(command-pilot.prevSteering)) > 2 {
+		pilot.prevSteering = command
+		app.Robot.Turn(command)
+	}
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// PreflightParams configures startup self-test
+type PreflightParams struct {
+	// Expected camera resolution, zero value accepts any size
+	ExpectedWidth  int
+	ExpectedHeight int
+
+	// FrameAttempts is a number of reads before video source is considered dead
+	FrameAttempts int
+
+	// MaxFrameAge is a maximal age of frame captured by running AI process
+	MaxFrameAge time.Duration
+}
+
+// DefaultPreflightParams returns params for 640x480 camera
+func DefaultPreflightParams() PreflightParams {
+	return PreflightParams{
+		ExpectedWidth:  640,
+		ExpectedHeight: 480,
+		FrameAttempts:  30,
+		MaxFrameAge:    2 * time.Second,
+	}
+}
+
+// PreflightCheck is a result of a single check
+type PreflightCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// PreflightReport is a machine-readable result of self-test
+type PreflightReport struct {
+	Passed bool             `json:"passed"`
+	Time   time.Time        `json:"time"`
+	Checks []PreflightCheck `json:"checks"`
+}
+
+func (report *PreflightReport) add(name string, err error) {
+	check := PreflightCheck{Name: name, Passed: err == nil}
+	if err != nil {
+		check.Detail = err.Error()
+		report.Passed = false
+	}
+	report.Checks = append(report.Checks, check)
+}
+
+// RunPreflight checks cascades, reference gallery, video source and car connection
+// Auto mode is refused until checks pass
+func (app *Application) RunPreflight() *PreflightReport {
+	report := &PreflightReport{Passed: true, Time: time.Now()}
+
+	for _, cascade := range signClasses {
+		report.add("cascade "+className(cascade), checkCascade(cascadePaths[cascade]))
+	}
+	for _, cascade := range signClasses {
+		report.add("gallery "+className(cascade), app.checkGallery(cascade))
+	}
+	report.add("video source", app.checkVideo())
+	report.add("car", app.Robot.Probe())
+
+	app.preflightMutex.Lock()
+	app.preflightReport = report
+	app.preflightMutex.Unlock()
+
+	if report.Passed {
+		app.Log.Info("Preflight passed")
+	} else {
+		for _, check := range report.Checks {
+			if !check.Passed {
+				app.reportError("Preflight check failed: "+check.Name, errors.New(check.Detail))
+			}
+		}
+		if !app.IsManual {
+			app.ChangeManual(true)
+		}
+	}
+
+	return report
+}
+
+// lastPreflight returns result of the latest self-test, nil if it was not run
+func (app *Application) lastPreflight() *PreflightReport {
+	app.preflightMutex.Lock()
+	defer app.preflightMutex.Unlock()
+
+	return app.preflightReport
+}
+
+// isRunning checks if AI process is active
+func (app *Application) isRunning() bool {
+	if app.done == nil {
+		return false
+	}
+
+	select {
+	case <-app.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func checkCascade(path string) error {
+	classifier := gocv.NewCascadeClassifier()
+	defer classifier.Close()
+
+	if !classifier.Load(path) {
+		return fmt.Errorf("%s can not be loaded", path)
+	}
+	return nil
+}
+
+func (app *Application) checkGallery(cascade int) error {
+	images := app.Gallery.Images[cascade]
+	if len(images) == 0 {
+		return fmt.Errorf("no reference images")
+	}
+
+	for i, img := range images {
+		if img.Empty() {
+			return fmt.Errorf("%s is empty", app.Gallery.Files[cascade][i])
+		}
+	}
+	return nil
+}
+
+// checkVideo uses frames of the running AI process, otherwise camera is opened for a moment
+func (app *Application) checkVideo() error {
+	var width, height int
+
+	if app.isRunning() {
+		var lastFrame time.Time
+		lastFrame, width, height = app.lastCapture()
+		if lastFrame.IsZero() {
+			return fmt.Errorf("no frames captured yet")
+		}
+		if age := time.Since(lastFrame); age > app.Preflight.MaxFrameAge {
+			return fmt.Errorf("last frame is %s old", age.Round(time.Millisecond))
+		}
+	} else {
+		webcam, err := gocv.OpenVideoCapture(app.Camera.Index)
+		if err != nil {
+			return err
+		}
+		defer webcam.Close()
+
+		// Resolution is checked with the saved profile of the device
+		if profile, err := app.Camera.Profile(); err == nil && len(profile) > 0 {
+			app.Camera.apply(webcam, profile)
+		}
+
+		img := gocv.NewMat()
+		defer img.Close()
+
+		for i := 0; i < app.Preflight.FrameAttempts && img.Empty(); i++ {
+			webcam.Read(&img)
+		}
+		if img.Empty() {
+			return fmt.Errorf("no frames after %d reads", app.Preflight.FrameAttempts)
+		}
+		width, height = img.Cols(), img.Rows()
+	}
+
+	expectedWidth, expectedHeight := app.Preflight.ExpectedWidth, app.Preflight.ExpectedHeight
+	if profile, err := app.Camera.Profile(); err == nil && profile["width"] > 0 && profile["height"] > 0 {
+		expectedWidth, expectedHeight = int(profile["width"]), int(profile["height"])
+	}
+
+	if expectedWidth > 0 && expectedHeight > 0 && (width != expectedWidth || height != expectedHeight) {
+		return fmt.Errorf("resolution %dx%d, expected %dx%d", width, height, expectedWidth, expectedHeight)
+	}
+	return nil
+}
@@ -0,0 +1,463 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"sync"
+
+	"gocv.io/x/gocv"
+)
+
+// UndistortStep removes lens distortion, coefficients come from camera calibration
+// Undistorted frame replaces the camera frame, so overlay and verifiers see the same geometry
+type UndistortStep struct {
+	Enabled bool `json:"enabled"`
+
+	// Camera matrix in pixels of calibrated resolution
+	Fx float64 `json:"fx"`
+	Fy float64 `json:"fy"`
+	Cx float64 `json:"cx"`
+	Cy float64 `json:"cy"`
+
+	// Distortion coefficients
+	K1 float64 `json:"k1"`
+	K2 float64 `json:"k2"`
+	P1 float64 `json:"p1"`
+	P2 float64 `json:"p2"`
+	K3 float64 `json:"k3"`
+}
+
+// CropStep restricts detection to a part of the frame in normalised coordinates
+type CropStep struct {
+	Enabled bool    `json:"enabled"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Width   float64 `json:"width"`
+	Height  float64 `json:"height"`
+}
+
+// ResizeStep downscales image for faster detection
+type ResizeStep struct {
+	Enabled bool    `json:"enabled"`
+	Scale   float64 `json:"scale"`
+}
+
+// GrayscaleStep converts image to a single channel
+type GrayscaleStep struct {
+	Enabled bool `json:"enabled"`
+}
+
+// CLAHEStep equalises histogram locally, color image is equalised by lightness
+type CLAHEStep struct {
+	Enabled   bool    `json:"enabled"`
+	ClipLimit float64 `json:"clip_limit"`
+	TileSize  int     `json:"tile_size"`
+}
+
+// GammaStep corrects brightness, gamma above 1 brightens dark frames
+type GammaStep struct {
+	Enabled bool    `json:"enabled"`
+	Gamma   float64 `json:"gamma"`
+}
+
+// BlurStep suppresses noise with Gaussian blur, kernel size must be odd
+type BlurStep struct {
+	Enabled    bool    `json:"enabled"`
+	KernelSize int     `json:"kernel_size"`
+	Sigma      float64 `json:"sigma"`
+}
+
+// PreprocessParams configures steps applied to the frame before cascade detection
+// Steps run in the order of fields, disabled steps are skipped
+type PreprocessParams struct {
+	Undistort UndistortStep `json:"undistort"`
+	Crop      CropStep      `json:"crop"`
+	Resize    ResizeStep    `json:"resize"`
+	Grayscale GrayscaleStep `json:"grayscale"`
+	CLAHE     CLAHEStep     `json:"clahe"`
+	Gamma     GammaStep     `json:"gamma"`
+	Blur      BlurStep      `json:"blur"`
+}
+
+// DefaultPreprocessParams returns disabled steps with typical parameters
+// Frames go to detection unchanged until steps are enabled
+func DefaultPreprocessParams() PreprocessParams {
+	return PreprocessParams{
+		Crop:   CropStep{Width: 1, Height: 1},
+		Resize: ResizeStep{Scale: 0.5},
+		CLAHE:  CLAHEStep{ClipLimit: 2.0, TileSize: 8},
+		Gamma:  GammaStep{Gamma: 1.5},
+		Blur:   BlurStep{KernelSize: 5},
+	}
+}
+
+// Validate checks parameters of enabled and disabled steps
+func (params PreprocessParams) Validate() error {
+	undistort := params.Undistort
+	if undistort.Enabled && (undistort.Fx <= 0 || undistort.Fy <= 0 || undistort.Cx <= 0 || undistort.Cy <= 0) {
+		return errors.New("undistort: fx, fy, cx and cy must be positive")
+	}
+
+	crop := params.Crop
+	if crop.X < 0 || crop.Y < 0 || crop.Width <= 0 || crop.Height <= 0 ||
+		crop.X+crop.Width > 1 || crop.Y+crop.Height > 1 {
+		return errors.New("crop: region must be inside 0..1")
+	}
+	if params.Resize.Scale < 0.1 || params.Resize.Scale > 1 {
+		return errors.New("resize: scale must be in 0.1..1")
+	}
+	if params.CLAHE.ClipLimit <= 0 || params.CLAHE.TileSize < 1 || params.CLAHE.TileSize > 64 {
+		return errors.New("clahe: clip_limit must be positive, tile_size must be in 1..64")
+	}
+	if params.Gamma.Gamma < 0.1 || params.Gamma.Gamma > 10 {
+		return errors.New("gamma: gamma must be in 0.1..10")
+	}
+	if params.Blur.KernelSize < 1 || params.Blur.KernelSize%2 == 0 || params.Blur.KernelSize > 31 {
+		return errors.New("blur: kernel_size must be odd and in 1..31")
+	}
+	if params.Blur.Sigma < 0 {
+		return errors.New("blur: sigma must not be negative")
+	}
+	return nil
+}
+
+// Summary describes enabled steps for the debug overlay
+func (params PreprocessParams) Summary() string {
+	var steps []string
+	if params.Undistort.Enabled {
+		steps = append(steps, fmt.Sprintf("undistort k1=%.3f k2=%.3f", params.Undistort.K1, params.Undistort.K2))
+	}
+	if params.Crop.Enabled {
+		steps = append(steps, fmt.Sprintf("crop %.2f,%.2f %.2fx%.2f",
+			params.Crop.X, params.Crop.Y, params.Crop.Width, params.Crop.Height))
+	}
+	if params.Resize.Enabled {
+		steps = append(steps, fmt.Sprintf("resize x%.2f", params.Resize.Scale))
+	}
+	if params.Grayscale.Enabled {
+		steps = append(steps, "gray")
+	}
+	if params.CLAHE.Enabled {
+		steps = append(steps, fmt.Sprintf("clahe %.1f/%d", params.CLAHE.ClipLimit, params.CLAHE.TileSize))
+	}
+	if params.Gamma.Enabled {
+		steps = append(steps, fmt.Sprintf("gamma %.2f", params.Gamma.Gamma))
+	}
+	if params.Blur.Enabled {
+		steps = append(steps, fmt.Sprintf("blur %d/%.1f", params.Blur.KernelSize, params.Blur.Sigma))
+	}
+	if len(steps) == 0 {
+		return "none"
+	}
+	return strings.Join(steps, ", ")
+}
+
+// cropRect returns crop region in pixels of the frame
+func (step CropStep) cropRect(cols int, rows int) image.Rectangle {
+	if !step.Enabled {
+		return image.Rect(0, 0, cols, rows)
+	}
+	rect := image.Rect(
+		int(step.X*float64(cols)), int(step.Y*float64(rows)),
+		int((step.X+step.Width)*float64(cols)), int((step.Y+step.Height)*float64(rows)))
+	return rect.Intersect(image.Rect(0, 0, cols, rows))
+}
+
+// detectionView maps boxes of preprocessed image back to the frame
+type detectionView struct {
+	// Region is a part of the frame searched by cascade
+	Region image.Rectangle
+	// Scale is a ratio of preprocessed image size to the region size
+	Scale float64
+}
+
+// toFrame converts boxes found on preprocessed image to frame coordinates
+func (view detectionView) toFrame(rects []image.Rectangle) []image.Rectangle {
+	res := make([]image.Rectangle, 0, len(rects))
+	for _, rect := range rects {
+		res = append(res, image.Rect(
+			int(float64(rect.Min.X)/view.Scale), int(float64(rect.Min.Y)/view.Scale),
+			int(float64(rect.Max.X)/view.Scale), int(float64(rect.Max.Y)/view.Scale)).Add(view.Region.Min))
+	}
+	return res
+}
+
+// Preprocessor prepares frames for detection
+// Parameters can be changed at any time, steps are run only by AI process
+type Preprocessor struct {
+	mutex  sync.Mutex
+	params PreprocessParams
+
+	// Undistortion maps are rebuilt when parameters or frame size change
+	undistortParams UndistortStep
+	undistortSize   image.Point
+	undistortX      gocv.Mat
+	undistortY      gocv.Mat
+
+	clahe       gocv.CLAHE
+	claheParams CLAHEStep
+	hasCLAHE    bool
+
+	gammaLUT   gocv.Mat
+	gammaValue float64
+}
+
+// NewPreprocessor constructs Preprocessor with the steps
+func NewPreprocessor(params PreprocessParams) (*Preprocessor, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res := &Preprocessor{}
+	res.params = params
+	res.undistortX = gocv.NewMat()
+	res.undistortY = gocv.NewMat()
+	res.gammaLUT = gocv.NewMat()
+	return res, nil
+}
+
+// Params returns current steps
+func (pre *Preprocessor) Params() PreprocessParams {
+	pre.mutex.Lock()
+	defer pre.mutex.Unlock()
+
+	return pre.params
+}
+
+// SetParams changes steps, they are applied from the next frame
+func (pre *Preprocessor) SetParams(params PreprocessParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	pre.mutex.Lock()
+	pre.params = params
+	pre.mutex.Unlock()
+	return nil
+}
+
+// Undistort replaces the frame with undistorted one
+func (pre *Preprocessor) Undistort(img *gocv.Mat, params PreprocessParams) {
+	step := params.Undistort
+	if !step.Enabled || img.Empty() {
+		return
+	}
+
+	size := image.Pt(img.Cols(), img.Rows())
+	if step != pre.undistortParams || size != pre.undistortSize || pre.undistortX.Empty() {
+		pre.buildUndistortMaps(step, size)
+	}
+
+	undistorted := gocv.NewMat()
+	defer undistorted.Close()
+
+	gocv.Remap(*img, &undistorted, &pre.undistortX, &pre.undistortY,
+		gocv.InterpolationLinear, gocv.BorderConstant, black)
+	undistorted.CopyTo(img)
+}
+
+// buildUndistortMaps computes pixel maps, it is too slow to be done for every frame
+func (pre *Preprocessor) buildUndistortMaps(step UndistortStep, size image.Point) {
+	camera := gocv.NewMatWithSize(3, 3, gocv.MatTypeCV64F)
+	defer camera.Close()
+	camera.SetDoubleAt(0, 0, step.Fx)
+	camera.SetDoubleAt(0, 2, step.Cx)
+	camera.SetDoubleAt(1, 1, step.Fy)
+	camera.SetDoubleAt(1, 2, step.Cy)
+	camera.SetDoubleAt(2, 2, 1)
+
+	distortion := gocv.NewMatWithSize(1, 5, gocv.MatTypeCV64F)
+	defer distortion.Close()
+	for i, value := range []float64{step.K1, step.K2, step.P1, step.P2, step.K3} {
+		distortion.SetDoubleAt(0, i, value)
+	}
+
+	rectification := gocv.NewMat()
+	defer rectification.Close()
+
+	gocv.InitUndistortRectifyMap(camera, distortion, rectification, camera, size,
+		int(gocv.MatTypeCV32F), pre.undistortX, pre.undistortY)
+	pre.undistortParams = step
+	pre.undistortSize = size
+}
+
+// Prepare runs the steps after undistortion and returns image for detection
+// Include polygons are in pixels of the frame, detection runs only inside them
+// Window limits detection to a part of the frame, zero value means the whole frame
+// Returned image is empty when nothing is left to search, it must be closed by caller
+func (pre *Preprocessor) Prepare(img gocv.Mat, params PreprocessParams, include [][]image.Point,
+	window image.Rectangle) (gocv.Mat, detectionView) {
+	crop := params.Crop.cropRect(img.Cols(), img.Rows())
+	if len(include) > 0 {
+		crop = crop.Intersect(polygonBounds(include))
+	}
+	if !window.Empty() {
+		crop = crop.Intersect(window)
+	}
+	view := detectionView{Region: crop, Scale: 1}
+	if crop.Empty() {
+		return gocv.NewMat(), view
+	}
+
+	region := img.Region(crop)
+	res := maskRegion(region, include, crop.Min)
+	region.Close()
+
+	if params.Resize.Enabled && params.Resize.Scale < 1 {
+		size := image.Pt(int(float64(res.Cols())*params.Resize.Scale), int(float64(res.Rows())*params.Resize.Scale))
+		if size.X > 0 && size.Y > 0 {
+			replaceImage(&res, func(dst *gocv.Mat) {
+				gocv.Resize(res, dst, size, 0, 0, gocv.InterpolationArea)
+			})
+			view.Scale = float64(size.X) / float64(crop.Dx())
+		}
+	}
+
+	if params.Grayscale.Enabled && res.Channels() == 3 {
+		replaceImage(&res, func(dst *gocv.Mat) {
+			gocv.CvtColor(res, dst, gocv.ColorBGRToGray)
+		})
+	}
+
+	if params.CLAHE.Enabled {
+		pre.equalize(&res, params.CLAHE)
+	}
+
+	if params.Gamma.Enabled && params.Gamma.Gamma != 1 {
+		if params.Gamma.Gamma != pre.gammaValue || pre.gammaLUT.Empty() {
+			pre.buildGammaLUT(params.Gamma.Gamma)
+		}
+		replaceImage(&res, func(dst *gocv.Mat) {
+			gocv.LUT(res, pre.gammaLUT, dst)
+		})
+	}
+
+	if params.Blur.Enabled && params.Blur.KernelSize > 1 {
+		kernel := image.Pt(params.Blur.KernelSize, params.Blur.KernelSize)
+		replaceImage(&res, func(dst *gocv.Mat) {
+			gocv.GaussianBlur(res, dst, kernel, params.Blur.Sigma, params.Blur.Sigma, gocv.BorderDefault)
+		})
+	}
+
+	return res, view
+}
+
+// polygonBounds returns bounding box of all polygons
+func polygonBounds(polygons [][]image.Point) image.Rectangle {
+	var res image.Rectangle
+	for _, polygon := range polygons {
+		for _, point := range polygon {
+			res = res.Union(image.Rectangle{Min: point, Max: point.Add(image.Pt(1, 1))})
+		}
+	}
+	return res
+}
+
+// maskRegion copies region, pixels outside of polygons are black
+// Polygons are in pixels of the frame, offset is a top left corner of the region
+func maskRegion(region gocv.Mat, polygons [][]image.Point, offset image.Point) gocv.Mat {
+	if len(polygons) == 0 {
+		return region.Clone()
+	}
+
+	var shifted [][]image.Point
+	for _, polygon := range polygons {
+		points := make([]image.Point, 0, len(polygon))
+		for _, point := range polygon {
+			points = append(points, point.Sub(offset))
+		}
+		shifted = append(shifted, points)
+	}
+
+	mask := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(0, 0, 0, 0), region.Rows(), region.Cols(), gocv.MatTypeCV8U)
+	defer mask.Close()
+	gocv.FillPoly(&mask, shifted, white)
+
+	res := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(0, 0, 0, 0), region.Rows(), region.Cols(), region.Type())
+	region.CopyToWithMask(&res, mask)
+	return res
+}
+
+// replaceImage runs operation into a new image and replaces img with it
+// OpenCV functions are not called in place, because some of them change size or channels
+func replaceImage(img *gocv.Mat, operation func(dst *gocv.Mat)) {
+	dst := gocv.NewMat()
+	operation(&dst)
+	img.Close()
+	*img = dst
+}
+
+// equalize applies CLAHE, color image is equalised by lightness channel of Lab
+func (pre *Preprocessor) equalize(img *gocv.Mat, step CLAHEStep) {
+	if !pre.hasCLAHE || step != pre.claheParams {
+		if pre.hasCLAHE {
+			pre.clahe.Close()
+		}
+		pre.clahe = gocv.NewCLAHEWithParams(step.ClipLimit, image.Pt(step.TileSize, step.TileSize))
+		pre.claheParams = step
+		pre.hasCLAHE = true
+	}
+
+	if img.Channels() == 1 {
+		replaceImage(img, func(dst *gocv.Mat) {
+			pre.clahe.Apply(*img, dst)
+		})
+		return
+	}
+
+	lab := gocv.NewMat()
+	defer lab.Close()
+	gocv.CvtColor(*img, &lab, gocv.ColorBGRToLab)
+
+	channels := gocv.Split(lab)
+	lightness := gocv.NewMat()
+	pre.clahe.Apply(channels[0], &lightness)
+	channels[0].Close()
+	channels[0] = lightness
+	gocv.Merge(channels, &lab)
+	for _, channel := range channels {
+		channel.Close()
+	}
+
+	replaceImage(img, func(dst *gocv.Mat) {
+		gocv.CvtColor(lab, dst, gocv.ColorLabToBGR)
+	})
+}
+
+// buildGammaLUT computes lookup table of gamma correction
+func (pre *Preprocessor) buildGammaLUT(gamma float64) {
+	pre.gammaLUT.Close()
+	pre.gammaLUT = gocv.NewMatWithSize(1, 256, gocv.MatTypeCV8U)
+	for i := 0; i < 256; i++ {
+		value := math.Pow(float64(i)/255, 1/gamma) * 255
+		pre.gammaLUT.SetUCharAt(0, i, uint8(math.Min(255, math.Round(value))))
+	}
+	pre.gammaValue = gamma
+}
+
+// Close releases cached maps and filters
+func (pre *Preprocessor) Close() {
+	pre.undistortX.Close()
+	pre.undistortY.Close()
+	pre.gammaLUT.Close()
+	if pre.hasCLAHE {
+		pre.clahe.Close()
+	}
+}
+
+// SetPreprocess changes preprocessing steps of the autopilot
+func (app *Application) SetPreprocess(params PreprocessParams) error {
+	if err := app.Preprocess.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Preprocessing changed", "steps", params.Summary())
+	return nil
+}
+
+// Preprocessing returns preprocessing steps of the autopilot
+func (app *Application) Preprocessing() PreprocessParams {
+	return app.Preprocess.Params()
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// Reasons of unusable frames
+const (
+	QualityBlurred     = "blurred"
+	QualityDark        = "dark"
+	QualityBright      = "bright"
+	QualityOverexposed = "overexposed"
+)
+
+// overexposedLevel is a brightness of saturated pixels
+const overexposedLevel = 250
+
+// QualityParams configures frame quality gate
+// Unusable frames are not processed, car holds the last command for MaxHold and halts after it
+type QualityParams struct {
+	Enabled bool `json:"enabled"`
+
+	// MinSharpness is a minimal variance of Laplacian, motion blur lowers it
+	MinSharpness float64 `json:"min_sharpness"`
+
+	// Mean brightness of grayscale frame, 0..255
+	MinBrightness float64 `json:"min_brightness"`
+	MaxBrightness float64 `json:"max_brightness"`
+
+	// MaxOverexposed is a maximal part of saturated pixels, 0..1
+	MaxOverexposed float64 `json:"max_overexposed"`
+
+	// MaxHold is a time in milliseconds the last command is held on unusable frames
+	MaxHold int `json:"max_hold_ms"`
+}
+
+// DefaultQualityParams returns thresholds, which skip only clearly unusable frames
+func DefaultQualityParams() QualityParams {
+	return QualityParams{
+		Enabled:        true,
+		MinSharpness:   20,
+		MinBrightness:  15,
+		MaxBrightness:  240,
+		MaxOverexposed: 0.5,
+		MaxHold:        500,
+	}
+}
+
+// Validate checks thresholds
+func (params QualityParams) Validate() error {
+	if params.MinSharpness < 0 {
+		return errors.New("min_sharpness must not be negative")
+	}
+	if params.MinBrightness < 0 || params.MaxBrightness > 255 || params.MinBrightness >= params.MaxBrightness {
+		return errors.New("brightness range must be inside 0..255")
+	}
+	if params.MaxOverexposed <= 0 || params.MaxOverexposed > 1 {
+		return errors.New("max_overexposed must be in 0..1")
+	}
+	if params.MaxHold < 0 || params.MaxHold > 10000 {
+		return errors.New("max_hold_ms must be in 0..10000")
+	}
+	return nil
+}
+
+// holdDuration returns MaxHold as a duration
+func (params QualityParams) holdDuration() time.Duration {
+	return time.Duration(params.MaxHold) * time.Millisecond
+}
+
+// FrameQuality is a result of frame assessment
+type FrameQuality struct {
+	Sharpness   float64 `json:"sharpness"`
+	Brightness  float64 `json:"brightness"`
+	Overexposed float64 `json:"overexposed"`
+	Usable      bool    `json:"usable"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// String describes quality for the debug overlay
+func (quality FrameQuality) String() string {
+	res := fmt.Sprintf("sharp %.0f, bright %.0f, overexp %.2f", quality.Sharpness, quality.Brightness, quality.Overexposed)
+	if !quality.Usable {
+		res += ", skipped: " + quality.Reason
+	}
+	return res
+}
+
+// QualityGate assesses frames before detection
+type QualityGate struct {
+	mutex  sync.Mutex
+	params QualityParams
+}
+
+// NewQualityGate constructs QualityGate with the thresholds
+func NewQualityGate(params QualityParams) (*QualityGate, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	res := &QualityGate{}
+	res.params = params
+	return res, nil
+}
+
+// Params returns current thresholds
+func (gate *QualityGate) Params() QualityParams {
+	gate.mutex.Lock()
+	defer gate.mutex.Unlock()
+
+	return gate.params
+}
+
+// SetParams changes thresholds, they are applied from the next frame
+func (gate *QualityGate) SetParams(params QualityParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	gate.mutex.Lock()
+	gate.params = params
+	gate.mutex.Unlock()
+	return nil
+}
+
+// Assess measures sharpness, brightness and overexposure of the frame
+// Frame is usable if gate is disabled or all measures are inside thresholds
+func (gate *QualityGate) Assess(img gocv.Mat, params QualityParams) FrameQuality {
+	res := FrameQuality{Usable: true}
+	if img.Empty() {
+		return res
+	}
+
+	gray := gocv.NewMat()
+	defer gray.Close()
+	if img.Channels() == 1 {
+		img.CopyTo(&gray)
+	} else {
+		gocv.CvtColor(img, &gray, gocv.ColorBGRToGray)
+	}
+
+	// Variance of Laplacian is low when edges are smeared
+	laplacian := gocv.NewMat()
+	defer laplacian.Close()
+	gocv.Laplacian(gray, &laplacian, int(gocv.MatTypeCV64F), 1, 1, 0, gocv.BorderDefault)
+
+	mean := gocv.NewMat()
+	defer mean.Close()
+	stdDev := gocv.NewMat()
+	defer stdDev.Close()
+	gocv.MeanStdDev(laplacian, &mean, &stdDev)
+	res.Sharpness = stdDev.GetDoubleAt(0, 0) * stdDev.GetDoubleAt(0, 0)
+
+	res.Brightness = gray.Mean().Val1
+
+	saturated := gocv.NewMat()
+	defer saturated.Close()
+	gocv.Threshold(gray, &saturated, overexposedLevel, 255, gocv.ThresholdBinary)
+	res.Overexposed = float64(gocv.CountNonZero(saturated)) / float64(gray.Rows()*gray.Cols())
+
+	qualityGauge.Set("sharpness", res.Sharpness)
+	qualityGauge.Set("brightness", res.Brightness)
+	qualityGauge.Set("overexposed", res.Overexposed)
+
+	if !params.Enabled {
+		return res
+	}
+	switch {
+	case res.Sharpness < params.MinSharpness:
+		res.Reason = QualityBlurred
+	case res.Brightness < params.MinBrightness:
+		res.Reason = QualityDark
+	case res.Brightness > params.MaxBrightness:
+		res.Reason = QualityBright
+	case res.Overexposed > params.MaxOverexposed:
+		res.Reason = QualityOverexposed
+	}
+	if res.Reason != "" {
+		res.Usable = false
+		framesSkipped.Inc(res.Reason)
+	}
+	return res
+}
+
+// SetQuality changes thresholds of the frame quality gate
+func (app *Application) SetQuality(params QualityParams) error {
+	if err := app.Quality.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Quality gate changed", "enabled", params.Enabled, "min_sharpness", params.MinSharpness,
+		"min_brightness", params.MinBrightness, "max_brightness", params.MaxBrightness,
+		"max_overexposed", params.MaxOverexposed, "max_hold_ms", params.MaxHold)
+	return nil
+}
+
+// QualityThresholds returns thresholds of the frame quality gate
+func (app *Application) QualityThresholds() QualityParams {
+	return app.Quality.Params()
+}
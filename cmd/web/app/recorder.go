@@ -0,0 +1,373 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// Defaults and limits of video recording
+const (
+	DefaultRecordingFPS      = 15
+	DefaultRecordingDuration = 10 * time.Minute
+
+	minRecordingDuration = 10 * time.Second
+	maxRecordingDuration = time.Hour
+	maxRecordingFPS      = 60
+	// recordingQueue is a number of frames waiting for encoder, further frames are skipped
+	recordingQueue = 32
+)
+
+// ErrRecordingRunning is returned when recording is already started
+var ErrRecordingRunning = errors.New("recording is already running")
+
+// RecordingParams describes recording, zero values select defaults of the Recorder
+type RecordingParams struct {
+	// MaxDuration is a length of a single file in seconds, next file is started after it
+	MaxDuration int     `json:"max_duration_s,omitempty"`
+	FPS         float64 `json:"fps,omitempty"`
+}
+
+// RecordingStatus describes the current or the last recording
+type RecordingStatus struct {
+	Recording   bool       `json:"recording"`
+	Dir         string     `json:"dir"`
+	File        string     `json:"file,omitempty"`
+	Files       []string   `json:"files"`
+	Frames      int        `json:"frames"`
+	Skipped     int        `json:"skipped"`
+	FPS         float64    `json:"fps"`
+	MaxDuration int        `json:"max_duration_s"`
+	Started     *time.Time `json:"started,omitempty"`
+	Finished    *time.Time `json:"finished,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// recordedFrame is an annotated frame waiting for encoder
+type recordedFrame struct {
+	img   gocv.Mat
+	frame uint64
+	time  time.Time
+}
+
+// recording encodes frames in a separate goroutine, so autopilot is not slowed down by disk
+type recording struct {
+	mutex       sync.Mutex
+	status      RecordingStatus
+	maxDuration time.Duration
+	frames      chan recordedFrame
+}
+
+// Recorder writes annotated autopilot view to MJPG/AVI files
+// Files are rotated after MaxDuration and named by the time of their first frame
+type Recorder struct {
+	// Dir is a directory of video files
+	Dir         string
+	FPS         float64
+	MaxDuration time.Duration
+
+	mutex  sync.Mutex
+	active *recording
+	last   *recording
+	wg     sync.WaitGroup
+}
+
+// NewRecorder constructs Recorder, which writes files into dir
+func NewRecorder(dir string) *Recorder {
+	res := &Recorder{}
+	res.Dir = dir
+	res.FPS = DefaultRecordingFPS
+	res.MaxDuration = DefaultRecordingDuration
+	return res
+}
+
+// Validate checks recording parameters
+func (params RecordingParams) Validate() error {
+	duration := time.Duration(params.MaxDuration) * time.Second
+	if params.MaxDuration != 0 && (duration < minRecordingDuration || duration > maxRecordingDuration) {
+		return fmt.Errorf("max_duration_s must be in %d..%d",
+			int(minRecordingDuration.Seconds()), int(maxRecordingDuration.Seconds()))
+	}
+	if params.FPS < 0 || params.FPS > maxRecordingFPS {
+		return fmt.Errorf("fps must be in 1..%d", maxRecordingFPS)
+	}
+	return nil
+}
+
+// Start begins recording of the next annotated frames
+func (recorder *Recorder) Start(params RecordingParams) (*RecordingStatus, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if recorder.active != nil {
+		return nil, ErrRecordingRunning
+	}
+	if err := os.MkdirAll(recorder.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	rec := &recording{}
+	rec.maxDuration = recorder.MaxDuration
+	if params.MaxDuration != 0 {
+		rec.maxDuration = time.Duration(params.MaxDuration) * time.Second
+	}
+
+	now := time.Now()
+	rec.frames = make(chan recordedFrame, recordingQueue)
+	rec.status = RecordingStatus{
+		Recording:   true,
+		Dir:         recorder.Dir,
+		Files:       []string{},
+		FPS:         recorder.FPS,
+		MaxDuration: int(rec.maxDuration.Seconds()),
+		Started:     &now,
+	}
+	if params.FPS != 0 {
+		rec.status.FPS = params.FPS
+	}
+
+	recorder.active = rec
+	recorder.last = rec
+
+	recorder.wg.Add(1)
+	go func() {
+		defer recorder.wg.Done()
+		recorder.write(rec)
+	}()
+
+	return rec.snapshot(), nil
+}
+
+// Stop finishes the current file, queued frames are written in background
+func (recorder *Recorder) Stop() *RecordingStatus {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if recorder.active != nil {
+		close(recorder.active.frames)
+		recorder.active = nil
+	}
+	return recorder.status()
+}
+
+// Status returns state of the current or the last recording
+func (recorder *Recorder) Status() *RecordingStatus {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return recorder.status()
+}
+
+// status must be called under the lock of the recorder
+func (recorder *Recorder) status() *RecordingStatus {
+	if recorder.last == nil {
+		return &RecordingStatus{Dir: recorder.Dir, Files: []string{},
+			FPS: recorder.FPS, MaxDuration: int(recorder.MaxDuration.Seconds())}
+	}
+	res := recorder.last.snapshot()
+	res.Recording = recorder.active != nil
+	return res
+}
+
+// snapshot copies status of the recording
+func (rec *recording) snapshot() *RecordingStatus {
+	rec.mutex.Lock()
+	defer rec.mutex.Unlock()
+
+	res := rec.status
+	res.Files = append([]string{}, rec.status.Files...)
+	return &res
+}
+
+// offer passes frame to the active recording, frame is copied only when it is needed
+func (recorder *Recorder) offer(img gocv.Mat, frameID uint64) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	rec := recorder.active
+	if rec == nil || img.Empty() {
+		return
+	}
+
+	// Only offer sends to the queue, so free space can't disappear before the send
+	if len(rec.frames) == cap(rec.frames) {
+		rec.mutex.Lock()
+		rec.status.Skipped++
+		rec.mutex.Unlock()
+		return
+	}
+	rec.frames <- recordedFrame{img: img.Clone(), frame: frameID, time: time.Now()}
+}
+
+// write encodes frames until recording is stopped or disk fails
+func (recorder *Recorder) write(rec *recording) {
+	var writer *gocv.VideoWriter
+	var size image.Point
+	var fileStarted time.Time
+	var written int
+	var failure error
+
+	for frame := range rec.frames {
+		if failure == nil {
+			frameSize := image.Pt(frame.img.Cols(), frame.img.Rows())
+
+			// File is rotated by duration, and when camera changes resolution
+			if writer != nil && (frame.time.Sub(fileStarted) >= rec.maxDuration || frameSize != size) {
+				writer.Close()
+				writer = nil
+			}
+			if writer == nil {
+				writer, failure = rec.open(frame.time, frameSize)
+				size = frameSize
+				fileStarted = frame.time
+				written = 0
+			}
+		}
+
+		if failure == nil {
+			// Autopilot rate differs from the rate of the file, so frames are dropped or repeated
+			// according to their time, and the video is played back at real speed
+			count := rec.framesDue(frame.time.Sub(fileStarted), written)
+			if count > 0 {
+				burnTimestamp(&frame.img, frame.time, frame.frame)
+			}
+			for i := 0; i < count && failure == nil; i++ {
+				failure = writer.Write(frame.img)
+				if failure == nil {
+					written++
+					rec.mutex.Lock()
+					rec.status.Frames++
+					rec.mutex.Unlock()
+				}
+			}
+		}
+		frame.img.Close()
+
+		if failure != nil {
+			// Recording is detached, so autopilot stops queueing frames
+			recorder.mutex.Lock()
+			if recorder.active == rec {
+				close(rec.frames)
+				recorder.active = nil
+			}
+			recorder.mutex.Unlock()
+		}
+	}
+
+	if writer != nil {
+		writer.Close()
+	}
+
+	finished := time.Now()
+	rec.mutex.Lock()
+	rec.status.Recording = false
+	rec.status.File = ""
+	rec.status.Finished = &finished
+	if failure != nil {
+		rec.status.Error = failure.Error()
+	}
+	rec.mutex.Unlock()
+}
+
+// framesDue returns how many times the frame is written to keep the rate of the file
+// Frame, which came at elapsed time since the file start, fills all empty slots up to its own slot
+func (rec *recording) framesDue(elapsed time.Duration, written int) int {
+	due := int(elapsed.Seconds()*rec.status.FPS) + 1
+	if due < written {
+		return 0
+	}
+	return due - written
+}
+
+// open creates the next video file of the recording
+func (rec *recording) open(started time.Time, size image.Point) (*gocv.VideoWriter, error) {
+	name, err := reserveFile(rec.status.Dir, "autopilot-"+started.Format("20060102-150405")+
+		fmt.Sprintf("-%03d", started.Nanosecond()/int(time.Millisecond)), ".avi")
+	if err != nil {
+		return nil, err
+	}
+	writer, err := gocv.VideoWriterFile(name, "MJPG", rec.status.FPS, size.X, size.Y, true)
+	if err != nil {
+		os.Remove(name)
+		return nil, err
+	}
+	if !writer.IsOpened() {
+		writer.Close()
+		os.Remove(name)
+		return nil, errors.New("video file " + name + " can not be opened")
+	}
+
+	rec.mutex.Lock()
+	rec.status.File = name
+	rec.status.Files = append(rec.status.Files, name)
+	rec.mutex.Unlock()
+	return writer, nil
+}
+
+// reserveFile creates an empty file with unique name, so previous recordings are never overwritten
+// Sequence suffix is added when the name is taken
+func reserveFile(dir string, base string, ext string) (string, error) {
+	for i := 0; i < 100; i++ {
+		name := filepath.Join(dir, base+ext)
+		if i > 0 {
+			name = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, ext))
+		}
+
+		file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return name, file.Close()
+	}
+	return "", errors.New("no free name for video file " + base + ext + " in " + dir)
+}
+
+// burnTimestamp prints time and number of the frame at the bottom left corner
+// Text is outlined, so it is readable on both dark and light background
+func burnTimestamp(img *gocv.Mat, t time.Time, frameID uint64) {
+	text := fmt.Sprintf("%s #%d", t.Format("2006-01-02 15:04:05.000"), frameID)
+	pt := image.Pt(8, img.Rows()-8)
+	gocv.PutText(img, text, pt, gocv.FontHersheyPlain, 1.1, black, 3)
+	gocv.PutText(img, text, pt, gocv.FontHersheyPlain, 1.1, white, 1)
+}
+
+// Close stops recording and waits until queued frames are written
+func (recorder *Recorder) Close() {
+	recorder.Stop()
+	recorder.wg.Wait()
+}
+
+// StartRecording begins recording of the annotated autopilot view
+func (app *Application) StartRecording(params RecordingParams) (*RecordingStatus, error) {
+	status, err := app.Recorder.Start(params)
+	if err != nil {
+		return nil, err
+	}
+	app.Log.Info("Recording started", "dir", status.Dir, "fps", status.FPS, "max_duration", status.MaxDuration)
+	return status, nil
+}
+
+// StopRecording finishes recording of the annotated autopilot view
+func (app *Application) StopRecording() *RecordingStatus {
+	status := app.Recorder.Stop()
+	app.Log.Info("Recording stopped", "frames", status.Frames, "files", len(status.Files))
+	return status
+}
+
+// Recording returns state of the current or the last recording
+func (app *Application) Recording() *RecordingStatus {
+	return app.Recorder.Status()
+}
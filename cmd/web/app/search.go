@@ -0,0 +1,165 @@
+package app
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+)
+
+// Kinds of detection scans
+const (
+	ScanFull   = "full"
+	ScanWindow = "window"
+	// ScanFallback is a full scan after the window missed the target
+	ScanFallback = "fallback"
+)
+
+// Parameters of the cascade, they are defaults of DetectMultiScale
+const (
+	cascadeScaleFactor  = 1.1
+	cascadeMinNeighbors = 3
+)
+
+// SearchParams configures search window around the previous target
+// While target is tracked, cascade scans expanded window around its predicted position at sizes close to it,
+// whole frame is scanned when the window misses and every FullScanEvery frames
+type SearchParams struct {
+	Enabled bool `json:"enabled"`
+
+	// Expand is a size of the window relative to the size of the target
+	Expand float64 `json:"expand"`
+
+	// ScaleRange limits detections to the size of the target divided or multiplied by it
+	ScaleRange float64 `json:"scale_range"`
+
+	// FullScanEvery is a number of window scans before the forced full scan
+	FullScanEvery int `json:"full_scan_every"`
+}
+
+// DefaultSearchParams returns disabled search window, so the whole frame is scanned as before
+func DefaultSearchParams() SearchParams {
+	return SearchParams{
+		Enabled:       false,
+		Expand:        3,
+		ScaleRange:    1.5,
+		FullScanEvery: 10,
+	}
+}
+
+// Validate checks window parameters
+func (params SearchParams) Validate() error {
+	if params.Expand < 1.5 || params.Expand > 10 {
+		return errors.New("expand must be in 1.5..10")
+	}
+	if params.ScaleRange < 1.1 || params.ScaleRange > 4 {
+		return errors.New("scale_range must be in 1.1..4")
+	}
+	if params.FullScanEvery < 1 || params.FullScanEvery > 1000 {
+		return errors.New("full_scan_every must be in 1..1000")
+	}
+	return nil
+}
+
+// searchWindow is a part of the frame and sizes of signs expected there
+type searchWindow struct {
+	Region  image.Rectangle
+	MinSize image.Point
+	MaxSize image.Point
+}
+
+// window returns search window around the target moved by its velocity
+// Zero value is returned when the window is outside of the frame
+func (params SearchParams) window(target image.Rectangle, velocity image.Point, cols int, rows int) searchWindow {
+	center := centerOf(target).Add(velocity)
+	half := image.Pt(int(float64(target.Dx())*params.Expand/2), int(float64(target.Dy())*params.Expand/2))
+	region := image.Rectangle{Min: center.Sub(half), Max: center.Add(half)}.Intersect(image.Rect(0, 0, cols, rows))
+	if region.Empty() {
+		return searchWindow{}
+	}
+
+	return searchWindow{
+		Region:  region,
+		MinSize: image.Pt(int(float64(target.Dx())/params.ScaleRange), int(float64(target.Dy())/params.ScaleRange)),
+		MaxSize: image.Pt(int(float64(target.Dx())*params.ScaleRange)+1, int(float64(target.Dy())*params.ScaleRange)+1),
+	}
+}
+
+// scaled converts size in pixels of the frame to pixels of preprocessed image
+func (view detectionView) scaled(size image.Point) image.Point {
+	return image.Pt(int(float64(size.X)*view.Scale), int(float64(size.Y)*view.Scale))
+}
+
+// searchMeter estimates detection rate of every scan kind, it is used only by AI process
+type searchMeter struct {
+	fps map[string]float64
+}
+
+// observe smooths rate of the scan and exports gain of the window scans
+func (meter *searchMeter) observe(scan string, elapsed time.Duration) {
+	if elapsed <= 0 {
+		return
+	}
+	if meter.fps == nil {
+		meter.fps = make(map[string]float64)
+	}
+
+	rate := 1 / elapsed.Seconds()
+	if previous, ok := meter.fps[scan]; ok {
+		rate = 0.9*previous + 0.1*rate
+	}
+	meter.fps[scan] = rate
+	searchFPS.Set(scan, rate)
+
+	if window, full := meter.fps[ScanWindow], meter.fps[ScanFull]; window > 0 && full > 0 {
+		searchGain.Set("", window/full)
+	}
+}
+
+// Search stores parameters of the search window, they can be changed at any time
+type Search struct {
+	mutex  sync.Mutex
+	params SearchParams
+}
+
+// NewSearch constructs Search with disabled window
+func NewSearch() *Search {
+	res := &Search{}
+	res.params = DefaultSearchParams()
+	return res
+}
+
+// Params returns current parameters
+func (search *Search) Params() SearchParams {
+	search.mutex.Lock()
+	defer search.mutex.Unlock()
+
+	return search.params
+}
+
+// SetParams changes parameters, they are applied from the next frame
+func (search *Search) SetParams(params SearchParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	search.mutex.Lock()
+	search.params = params
+	search.mutex.Unlock()
+	return nil
+}
+
+// SetSearch changes search window of the autopilot
+func (app *Application) SetSearch(params SearchParams) error {
+	if err := app.Search.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Search window changed", "enabled", params.Enabled, "expand", params.Expand,
+		"scale_range", params.ScaleRange, "full_scan_every", params.FullScanEvery)
+	return nil
+}
+
+// SearchMode returns parameters of the search window
+func (app *Application) SearchMode() SearchParams {
+	return app.Search.Params()
+}
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"errors"
+	"image"
+	"sync"
+
+	"gocv.io/x/gocv"
+)
+
+// ShapeParams describes expected geometry of the sign contour
+type ShapeParams struct {
+	Enabled bool `json:"enabled"`
+
+	// Vertices is a number of corners of approximated polygon
+	// Zero value means that sign is a circle and HoughCircles is used instead
+	Vertices int `json:"vertices"`
+
+	// VertexTolerance allows approximation to have a few extra or missing corners
+	VertexTolerance int `json:"vertex_tolerance"`
+
+	// Epsilon is an ApproxPolyDP accuracy as a part of contour perimeter
+	Epsilon float64 `json:"epsilon"`
+
+	// MinAreaRatio is a minimal part of candidate region covered by the dominant contour
+	MinAreaRatio float64 `json:"min_area_ratio"`
+
+	// MinRadiusRatio is a minimal circle radius as a part of half of candidate side
+	MinRadiusRatio float64 `json:"min_radius_ratio"`
+}
+
+// Validate checks geometry of a single sign
+func (params ShapeParams) Validate() error {
+	if params.Vertices != 0 && (params.Vertices < 3 || params.Vertices > 16) {
+		return errors.New("vertices must be 0 for a circle or in 3..16")
+	}
+	if params.VertexTolerance < 0 || params.VertexTolerance > 4 {
+		return errors.New("vertex_tolerance must be in 0..4")
+	}
+	if params.Vertices != 0 && (params.Epsilon <= 0 || params.Epsilon > 0.2) {
+		return errors.New("epsilon must be in 0..0.2")
+	}
+	if params.MinAreaRatio < 0 || params.MinAreaRatio > 1 {
+		return errors.New("min_area_ratio must be in 0..1")
+	}
+	if params.MinRadiusRatio < 0 || params.MinRadiusRatio > 1 {
+		return errors.New("min_radius_ratio must be in 0..1")
+	}
+	return nil
+}
+
+// ShapeThresholds are geometry params of every sign class
+type ShapeThresholds struct {
+	Stop   ShapeParams `json:"stop"`
+	Circle ShapeParams `json:"circle"`
+	Yield  ShapeParams `json:"yield"`
+}
+
+// DefaultShapeThresholds returns geometry of the signs
+func DefaultShapeThresholds() ShapeThresholds {
+	return ShapeThresholds{
+		// Stop sign is an octagon
+		Stop: ShapeParams{
+			Enabled:         true,
+			Vertices:        8,
+			VertexTolerance: 1,
+			Epsilon:         0.02,
+			MinAreaRatio:    0.3,
+		},
+		// Circle sign is checked by Hough transform
+		Circle: ShapeParams{
+			Enabled:        true,
+			Vertices:       0,
+			MinRadiusRatio: 0.6,
+		},
+		// Yield sign is a triangle
+		Yield: ShapeParams{
+			Enabled:         true,
+			Vertices:        3,
+			VertexTolerance: 0,
+			Epsilon:         0.04,
+			MinAreaRatio:    0.2,
+		},
+	}
+}
+
+// Validate checks params of every sign
+func (thresholds ShapeThresholds) Validate() error {
+	if err := thresholds.Stop.Validate(); err != nil {
+		return errors.New("stop: " + err.Error())
+	}
+	if err := thresholds.Circle.Validate(); err != nil {
+		return errors.New("circle: " + err.Error())
+	}
+	if err := thresholds.Yield.Validate(); err != nil {
+		return errors.New("yield: " + err.Error())
+	}
+	return nil
+}
+
+// forClass returns params of the cascade type, unknown types are not checked
+func (thresholds ShapeThresholds) forClass(cascade int) (ShapeParams, bool) {
+	switch cascade {
+	case StopCascade:
+		return thresholds.Stop, true
+	case CircleCascade:
+		return thresholds.Circle, true
+	case YieldCascade:
+		return thresholds.Yield, true
+	}
+	return ShapeParams{}, false
+}
+
+// ShapeVerifier checks contour geometry of cascade candidates
+// Thresholds can be changed at any time
+type ShapeVerifier struct {
+	mutex  sync.Mutex
+	params ShapeThresholds
+}
+
+// NewShapeVerifier constructs ShapeVerifier with default params for every sign
+func NewShapeVerifier() *ShapeVerifier {
+	res := &ShapeVerifier{}
+	res.params = DefaultShapeThresholds()
+	return res
+}
+
+// Params returns current thresholds
+func (verifier *ShapeVerifier) Params() ShapeThresholds {
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+
+	return verifier.params
+}
+
+// SetParams changes thresholds, they are applied from the next candidate
+func (verifier *ShapeVerifier) SetParams(params ShapeThresholds) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	verifier.mutex.Lock()
+	verifier.params = params
+	verifier.mutex.Unlock()
+	return nil
+}
+
+// Verify returns true if candidate region of the frame has expected shape
+// Classes without params or with disabled check always pass
+func (verifier *ShapeVerifier) Verify(frame gocv.Mat, rect image.Rectangle, cascade int) bool {
+	params, ok := verifier.Params().forClass(cascade)
+	if !ok || !params.Enabled {
+		return true
+	}
+
+	region := frame.Region(rect)
+	defer region.Close()
+
+	gray := gocv.NewMat()
+	defer gray.Close()
+
+	if region.Channels() == 1 {
+		region.CopyTo(&gray)
+	} else {
+		gocv.CvtColor(region, &gray, gocv.ColorBGRToGray)
+	}
+	gocv.GaussianBlur(gray, &gray, image.Pt(5, 5), 0, 0, gocv.BorderDefault)
+
+	if params.Vertices == 0 {
+		return verifyCircle(gray, params)
+	}
+	return verifyPolygon(gray, params)
+}
+
+// verifyPolygon approximates the dominant contour and compares number of its corners
+func verifyPolygon(gray gocv.Mat, params ShapeParams) bool {
+	edges := gocv.NewMat()
+	defer edges.Close()
+	gocv.Canny(gray, &edges, 50, 150)
+
+	contours := gocv.FindContours(edges, gocv.RetrievalExternal, gocv.ChainApproxSimple)
+	if len(contours) == 0 {
+		return false
+	}
+
+	// Dominant contour is the one with largest area
+	maxAreaIndex := -1
+	maxArea := 0.0
+	for i, contour := range contours {
+		area := gocv.ContourArea(contour)
+		if area > maxArea {
+			maxArea = area
+			maxAreaIndex = i
+		}
+	}
+	if maxAreaIndex < 0 {
+		return false
+	}
+
+	regionArea := float64(gray.Rows() * gray.Cols())
+	if maxArea < regionArea*params.MinAreaRatio {
+		return false
+	}
+
+	contour := contours[maxAreaIndex]
+	epsilon := params.Epsilon * gocv.ArcLength(contour, true)
+	approx := gocv.ApproxPolyDP(contour, epsilon, true)
+
+	diff := len(approx) - params.Vertices
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= params.VertexTolerance
+}
+
+// verifyCircle looks for a circle which fills the most part of region
+func verifyCircle(gray gocv.Mat, params ShapeParams) bool {
+	circles := gocv.NewMat()
+	defer circles.Close()
+
+	side := gray.Rows()
+	if gray.Cols() < side {
+		side = gray.Cols()
+	}
+	minRadius := int(float64(side) / 2 * params.MinRadiusRatio)
+
+	gocv.HoughCirclesWithParams(gray, &circles, gocv.HoughGradient, 1, float64(side), 100, 30, minRadius, side/2+2)
+
+	return !circles.Empty() && circles.Cols() > 0
+}
+
+// SetShape changes contour geometry thresholds of the signs
+func (app *Application) SetShape(params ShapeThresholds) error {
+	if err := app.Shape.SetParams(params); err != nil {
+		return err
+	}
+	app.Log.Info("Shape thresholds changed", "stop", params.Stop.Enabled, "circle", params.Circle.Enabled,
+		"yield", params.Yield.Enabled)
+	return nil
+}
+
+// ShapeThresholds returns contour geometry thresholds of the signs
+func (app *Application) ShapeThresholds() ShapeThresholds {
+	return app.Shape.Params()
+}
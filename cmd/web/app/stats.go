@@ -0,0 +1,59 @@
+package app
+
+import (
+	"sync"
+)
+
+// Names of the filtering stages
+const (
+	StageMask     = "mask"
+	StageGeometry = "geometry"
+	StageShape    = "shape"
+	StageHash     = "hash"
+)
+
+// StageCounter stores results of a single filtering stage
+type StageCounter struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// StageStats counts candidates which passed or failed every filtering stage
+type StageStats struct {
+	mutex  sync.Mutex
+	stages map[string]StageCounter
+}
+
+// Record saves result of the stage for a single candidate
+func (stats *StageStats) Record(stage string, passed bool) {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	counter := stats.stages[stage]
+	if passed {
+		counter.Passed++
+	} else {
+		counter.Failed++
+		rejectionsTotal.Inc(stage)
+	}
+	stats.stages[stage] = counter
+}
+
+// Snapshot returns copy of all counters
+func (stats *StageStats) Snapshot() map[string]StageCounter {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	res := make(map[string]StageCounter, len(stats.stages))
+	for stage, counter := range stats.stages {
+		res[stage] = counter
+	}
+	return res
+}
+
+// NewStageStats constructs empty StageStats
+func NewStageStats() *StageStats {
+	res := &StageStats{}
+	res.stages = make(map[string]StageCounter)
+	return res
+}
@@ -0,0 +1,28 @@
+package app
+
+// Status is a snapshot of Application state for the Web Server
+type Status struct {
+	IsManual    bool                    `json:"manual"`
+	IsBlocked   bool                    `json:"blocked"`
+	CascadeType int                     `json:"cascade"`
+	Overlay     int                     `json:"overlay"`
+	Acquisition AcquisitionStatus       `json:"acquisition"`
+	Stages      map[string]StageCounter `json:"stages"`
+	Preflight   *PreflightReport        `json:"preflight,omitempty"`
+	Telemetry   Telemetry               `json:"telemetry"`
+}
+
+// Status returns current state of Application
+func (app *Application) Status() Status {
+	res := Status{}
+	res.IsManual = app.IsManual
+	res.IsBlocked = app.IsBlocked
+	res.CascadeType = app.CascadeType
+	res.Overlay = app.Overlay
+	res.Acquisition = app.Acquisition.Status()
+	res.Stages = app.Stats.Snapshot()
+	res.Preflight = app.lastPreflight()
+	res.Telemetry = app.Telemetry()
+
+	return res
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// frameBuffer keeps the latest frame for streaming and snapshots
+type frameBuffer struct {
+	mutex sync.Mutex
+	// frame is annotated frame encoded as JPEG
+	frame []byte
+	seq   uint64
+
+	// raw is a copy of the latest camera frame, annotated is the latest processed frame with overlay
+	raw       gocv.Mat
+	annotated gocv.Mat
+	frameID   uint64
+	time      time.Time
+}
+
+// storeRaw copies camera frame before it is undistorted and overlay is drawn
+func (app *Application) storeRaw(img gocv.Mat, frameID uint64) {
+	app.frames.mutex.Lock()
+	img.CopyTo(&app.frames.raw)
+	app.frames.frameID = frameID
+	app.frames.time = time.Now()
+	app.frames.mutex.Unlock()
+}
+
+// publishFrame encodes annotated frame for streaming
+func (app *Application) publishFrame(img gocv.Mat) {
+	if img.Empty() {
+		return
+	}
+
+	buf, err := gocv.IMEncode(gocv.JPEGFileExt, img)
+	if err != nil {
+		return
+	}
+
+	app.frames.mutex.Lock()
+	img.CopyTo(&app.frames.annotated)
+	app.frames.frame = buf
+	app.frames.seq++
+	app.frames.mutex.Unlock()
+}
+
+// LatestFrame returns the latest annotated JPEG frame and its sequence number
+func (app *Application) LatestFrame() ([]byte, uint64) {
+	app.frames.mutex.Lock()
+	defer app.frames.mutex.Unlock()
+
+	return app.frames.frame, app.frames.seq
+}
+
+// Snapshot kinds and formats
+const (
+	SnapshotRaw       = "raw"
+	SnapshotAnnotated = "annotated"
+
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+// maxSnapshotSide limits size of resized snapshot
+const maxSnapshotSide = 4096
+
+// ErrNoFrame is returned when autopilot did not process any frame yet
+var ErrNoFrame = errors.New("no frame processed yet")
+
+// SnapshotParams selects frame and its encoding
+// Zero Width or Height keeps aspect ratio, both zero keep original size
+type SnapshotParams struct {
+	Kind   string
+	Format string
+	Width  int
+	Height int
+}
+
+// Validate checks snapshot parameters
+func (params SnapshotParams) Validate() error {
+	if params.Kind != SnapshotRaw && params.Kind != SnapshotAnnotated {
+		return errors.New("kind must be raw or annotated")
+	}
+	if params.Format != FormatJPEG && params.Format != FormatPNG {
+		return errors.New("format must be jpeg or png")
+	}
+	if params.Width < 0 || params.Height < 0 || params.Width > maxSnapshotSide || params.Height > maxSnapshotSide {
+		return errors.New("width and height must be in 0..4096")
+	}
+	return nil
+}
+
+// Snapshot is an encoded frame
+type Snapshot struct {
+	Image       []byte
+	ContentType string
+	Frame       uint64
+	Time        time.Time
+}
+
+// fileExt returns extension of the image format
+func fileExt(format string) gocv.FileExt {
+	if format == FormatPNG {
+		return gocv.PNGFileExt
+	}
+	return gocv.JPEGFileExt
+}
+
+// contentType returns MIME type of the image format
+func contentType(format string) string {
+	if format == FormatPNG {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// snapshotSize returns size of resized frame
+func snapshotSize(cols int, rows int, width int, height int) image.Point {
+	switch {
+	case width == 0 && height == 0:
+		return image.Pt(cols, rows)
+	case height == 0:
+		height = rows * width / cols
+	case width == 0:
+		width = cols * height / rows
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	return image.Pt(width, height)
+}
+
+// Snapshot encodes the latest processed frame
+func (app *Application) Snapshot(params SnapshotParams) (*Snapshot, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	img := gocv.NewMat()
+	defer img.Close()
+
+	app.frames.mutex.Lock()
+	source := app.frames.raw
+	if params.Kind == SnapshotAnnotated {
+		source = app.frames.annotated
+	}
+	if source.Empty() {
+		app.frames.mutex.Unlock()
+		return nil, ErrNoFrame
+	}
+	source.CopyTo(&img)
+	res := &Snapshot{Frame: app.frames.frameID, Time: app.frames.time}
+	app.frames.mutex.Unlock()
+
+	resized := gocv.NewMat()
+	defer resized.Close()
+
+	output := img
+	size := snapshotSize(img.Cols(), img.Rows(), params.Width, params.Height)
+	if size.X != img.Cols() || size.Y != img.Rows() {
+		gocv.Resize(img, &resized, size, 0, 0, gocv.InterpolationArea)
+		output = resized
+	}
+
+	buf, err := gocv.IMEncode(fileExt(params.Format), output)
+	if err != nil {
+		return nil, err
+	}
+	res.Image = buf
+	res.ContentType = contentType(params.Format)
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+// Telemetry describes the latest car control values
+type Telemetry struct {
+	Throttle       int       `json:"throttle"`
+	Steering       int       `json:"steering"`
+	FPS            float64   `json:"fps"`
+	FailureCounter int       `json:"failure_counter"`
+	HasTarget      bool      `json:"has_target"`
+	Updated        time.Time `json:"updated"`
+}
+
+// telemetryState stores Telemetry between AI process and Web Server
+type telemetryState struct {
+	mutex sync.Mutex
+	value Telemetry
+}
+
+// setTelemetry saves control values of the processed frame
+func (app *Application) setTelemetry(report *FrameReport, fps float64) {
+	app.telemetry.mutex.Lock()
+	defer app.telemetry.mutex.Unlock()
+
+	app.telemetry.value = Telemetry{
+		Throttle:       report.Throttle,
+		Steering:       report.Steering,
+		FPS:            fps,
+		FailureCounter: report.FailureCounter,
+		HasTarget:      report.HasTarget,
+		Updated:        time.Now(),
+	}
+}
+
+// setManualSteering saves steering of manual command
+func (app *Application) setManualSteering(steering int) {
+	app.telemetry.mutex.Lock()
+	defer app.telemetry.mutex.Unlock()
+
+	app.telemetry.value.Steering = steering
+	app.telemetry.value.Updated = time.Now()
+}
+
+// setManualThrottle saves throttle of manual command, negative value means driving backward
+func (app *Application) setManualThrottle(throttle int) {
+	app.telemetry.mutex.Lock()
+	defer app.telemetry.mutex.Unlock()
+
+	app.telemetry.value.Throttle = throttle
+	app.telemetry.value.Updated = time.Now()
+}
+
+// Telemetry returns the latest control values
+func (app *Application) Telemetry() Telemetry {
+	app.telemetry.mutex.Lock()
+	defer app.telemetry.mutex.Unlock()
+
+	return app.telemetry.value
+}
@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is a severity of the log record
+type Level int
+
+// Supported levels
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+func (level Level) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseLevel converts name of the level to Level
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if levelName == strings.ToLower(name) {
+			return level, nil
+		}
+	}
+	return InfoLevel, errors.New("unknown log level: " + name)
+}
+
+// Output formats
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+// Logger writes structured records with per-component verbosity
+type Logger struct {
+	mutex        sync.Mutex
+	out          io.Writer
+	format       string
+	defaultLevel Level
+	// levels stores only explicit verbosity, other components follow the default level
+	levels     map[string]Level
+	components map[string]bool
+}
+
+// New constructs Logger, format is logfmt or json
+func New(out io.Writer, format string, level Level) (*Logger, error) {
+	if format != FormatLogfmt && format != FormatJSON {
+		return nil, errors.New("unknown log format: " + format)
+	}
+
+	res := &Logger{}
+	res.out = out
+	res.format = format
+	res.defaultLevel = level
+	res.levels = make(map[string]Level)
+	res.components = make(map[string]bool)
+	return res, nil
+}
+
+// SetLevel changes verbosity of the component
+// Empty component changes the default level
+func (logger *Logger) SetLevel(component string, level Level) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if component == "" {
+		logger.defaultLevel = level
+	} else {
+		logger.levels[component] = level
+	}
+}
+
+// Levels returns verbosity of every known component
+func (logger *Logger) Levels() map[string]string {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	res := make(map[string]string)
+	res["default"] = logger.defaultLevel.String()
+	for component := range logger.components {
+		res[component] = logger.level(component).String()
+	}
+	for component := range logger.levels {
+		res[component] = logger.level(component).String()
+	}
+	return res
+}
+
+// level must be called under the lock of the logger
+func (logger *Logger) level(component string) Level {
+	if level, ok := logger.levels[component]; ok {
+		return level
+	}
+	return logger.defaultLevel
+}
+
+func (logger *Logger) enabled(component string, level Level) bool {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	return level >= logger.level(component)
+}
+
+// Component returns Entry, which marks records with the component name
+// Component is registered, so it is visible in Levels, and follows the default level until it is set
+func (logger *Logger) Component(name string) *Entry {
+	logger.mutex.Lock()
+	logger.components[name] = true
+	logger.mutex.Unlock()
+
+	return &Entry{logger: logger, component: name}
+}
+
+// Entry is a logger of a component with attached fields
+type Entry struct {
+	logger    *Logger
+	component string
+	fields    []interface{}
+}
+
+// With returns Entry with additional key-value pairs
+func (entry *Entry) With(keyValues ...interface{}) *Entry {
+	res := &Entry{logger: entry.logger, component: entry.component}
+	res.fields = append(append(res.fields, entry.fields...), keyValues...)
+	return res
+}
+
+// Debug writes a record with debug level
+func (entry *Entry) Debug(msg string, keyValues ...interface{}) {
+	entry.log(DebugLevel, msg, keyValues)
+}
+
+// Info writes a record with info level
+func (entry *Entry) Info(msg string, keyValues ...interface{}) {
+	entry.log(InfoLevel, msg, keyValues)
+}
+
+// Warn writes a record with warn level
+func (entry *Entry) Warn(msg string, keyValues ...interface{}) {
+	entry.log(WarnLevel, msg, keyValues)
+}
+
+// Error writes a record with error level
+func (entry *Entry) Error(msg string, keyValues ...interface{}) {
+	entry.log(ErrorLevel, msg, keyValues)
+}
+
+func (entry *Entry) log(level Level, msg string, keyValues []interface{}) {
+	if !entry.logger.enabled(entry.component, level) {
+		return
+	}
+
+	keys := []string{"time", "level", "component", "msg"}
+	values := map[string]interface{}{
+		"time":      time.Now().Format(time.RFC3339Nano),
+		"level":     level.String(),
+		"component": entry.component,
+		"msg":       msg,
+	}
+
+	all := append(append([]interface{}{}, entry.fields...), keyValues...)
+	for i := 0; i < len(all); i += 2 {
+		key := fmt.Sprint(all[i])
+		var value interface{} = "MISSING"
+		if i+1 < len(all) {
+			value = all[i+1]
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+
+	var line string
+	if entry.logger.format == FormatJSON {
+		line = formatJSON(keys, values)
+	} else {
+		line = formatLogfmt(keys, values)
+	}
+
+	entry.logger.mutex.Lock()
+	io.WriteString(entry.logger.out, line+"\n")
+	entry.logger.mutex.Unlock()
+}
+
+func formatLogfmt(keys []string, values map[string]interface{}) string {
+	var builder strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+		value := fmt.Sprint(values[key])
+		if value == "" || strings.ContainsAny(value, " =\"") {
+			value = strconv.Quote(value)
+		}
+		builder.WriteString(key + "=" + value)
+	}
+	return builder.String()
+}
+
+func formatJSON(keys []string, values map[string]interface{}) string {
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		keyJSON, _ := json.Marshal(key)
+		valueJSON, err := json.Marshal(values[key])
+		if err != nil {
+			valueJSON, _ = json.Marshal(fmt.Sprint(values[key]))
+		}
+		builder.Write(keyJSON)
+		builder.WriteByte(':')
+		builder.Write(valueJSON)
+	}
+	builder.WriteByte('}')
+	return builder.String()
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestComponentFollowsDefaultLevel(t *testing.T) {
+	var out bytes.Buffer
+	log, err := New(&out, FormatLogfmt, InfoLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := log.Component("app")
+
+	entry.Debug("hidden")
+	if out.Len() != 0 {
+		t.Fatalf("debug record written with info level: %q", out.String())
+	}
+
+	log.SetLevel("", DebugLevel)
+	entry.Debug("visible")
+	if !bytes.Contains(out.Bytes(), []byte("msg=visible")) {
+		t.Fatalf("component does not follow default level, output: %q", out.String())
+	}
+	if levels := log.Levels(); levels["app"] != "debug" || levels["default"] != "debug" {
+		t.Fatalf("unexpected levels: %v", levels)
+	}
+}
+
+func TestComponentKeepsExplicitLevel(t *testing.T) {
+	var out bytes.Buffer
+	log, err := New(&out, FormatLogfmt, InfoLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := log.Component("app")
+
+	log.SetLevel("app", ErrorLevel)
+	log.SetLevel("", DebugLevel)
+	entry.Warn("hidden")
+	if out.Len() != 0 {
+		t.Fatalf("warn record written with explicit error level: %q", out.String())
+	}
+	if levels := log.Levels(); levels["app"] != "error" {
+		t.Fatalf("unexpected levels: %v", levels)
+	}
+}
@@ -1,37 +1,187 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/RadiumByte/Robot-Server/cmd/web/api"
 	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
 	"github.com/RadiumByte/Robot-Server/cmd/web/ral"
+	"github.com/RadiumByte/Robot-Server/cmd/web/rpc"
+)
+
+// Exit codes
+const (
+	exitOK           = 0
+	exitStartFailure = 1
+	exitServeFailure = 2
+	exitHaltFailure  = 3
 )
 
 func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	logFormat := flag.String("log-format", logger.FormatLogfmt, "log output format: logfmt or json")
+	logLevel := flag.String("log-level", "info", "default log level: debug, info, warn or error")
+	tokenFile := flag.String("tokens", "", "JSON file with API tokens and roles, empty disables authentication")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate of the server, enables HTTPS")
+	tlsKey := flag.String("tls-key", "", "PEM private key of the server")
+	tlsClientCA := flag.String("tls-client-ca", "", "PEM CA of operator machines, enables client certificate verification")
+	carCA := flag.String("car-ca", "", "PEM CA of the car, enables HTTPS to the car")
+	carServerName := flag.String("car-server-name", "", "expected name in the car's certificate, car IP by default")
+	grpcPort := flag.String("grpc", "", "address of gRPC service, e.g. :9090, empty disables it")
+	captureDir := flag.String("capture-dir", "captures", "directory for frames saved by capture requests")
+	recordDir := flag.String("record-dir", "recordings", "directory for video recordings of the autopilot view")
+	recordFPS := flag.Float64("record-fps", app.DefaultRecordingFPS, "frame rate of video recordings")
+	recordDuration := flag.Duration("record-max-duration", app.DefaultRecordingDuration, "length of a single video file, next file is started after it")
+	cameraProfiles := flag.String("camera-profiles", "camera-profiles.json", "JSON file with camera properties of every device")
+	flag.Parse()
+
+	level, err := logger.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Println(err)
+		return exitStartFailure
+	}
+	log, err := logger.New(os.Stdout, *logFormat, level)
+	if err != nil {
+		fmt.Println(err)
+		return exitStartFailure
+	}
+	mainLog := log.Component("main")
+
 	CarIP := "192.168.183.50"
 	Port := ":8080"
 	// Test
-	robot, err := ral.NewRoboCar(CarIP, Port)
+	robot, err := ral.NewRoboCar(CarIP, Port, log)
 	if err != nil {
-		fmt.Println(err)
-		fmt.Println("Robot connection failure - program stopped")
-		return
+		mainLog.Error("Robot connection failure - program stopped", "err", err)
+		return exitStartFailure
+	}
+	if *carCA != "" {
+		if err := robot.UseTLS(*carCA, *carServerName); err != nil {
+			mainLog.Error("Car TLS configuration failure - program stopped", "err", err)
+			return exitStartFailure
+		}
 	}
 
-	application, err := app.NewApplication(robot)
+	application, err := app.NewApplication(robot, log)
 	if err != nil {
-		fmt.Println(err)
-		fmt.Println("Application start failure - program stopped")
-		return
+		mainLog.Error("Application start failure - program stopped", "err", err)
+		return exitStartFailure
+	}
+	application.Captures.Dir = *captureDir
+	application.Recorder.Dir = *recordDir
+	application.Recorder.FPS = *recordFPS
+	application.Recorder.MaxDuration = *recordDuration
+	application.Camera.ProfilePath = *cameraProfiles
+
+	// Failed preflight does not stop the program, but auto mode is refused
+	report := application.RunPreflight()
+	if body, err := json.Marshal(report); err == nil {
+		mainLog.Info("Preflight report", "report", string(body))
+	}
+
+	// Tokens and TLS are shared by HTTP and gRPC
+	var tokens *api.TokenStore
+	if *tokenFile != "" {
+		tokens, err = api.NewTokenStore(*tokenFile)
+		if err != nil {
+			mainLog.Error("Token file failure - program stopped", "err", err)
+			return exitStartFailure
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if *tlsCert != "" || *tlsKey != "" {
+		tlsConfig, err = api.NewServerTLS(*tlsCert, *tlsKey, *tlsClientCA)
+		if err != nil {
+			mainLog.Error("Server TLS configuration failure - program stopped", "err", err)
+			return exitStartFailure
+		}
+	} else if *tlsClientCA != "" {
+		mainLog.Error("Client certificate verification requires -tls-cert and -tls-key - program stopped")
+		return exitStartFailure
 	}
 
-	server, err := api.NewWebServer(application)
+	server, err := api.NewWebServer(application, log, tokens)
 	if err != nil {
-		fmt.Println(err)
-		fmt.Println("Server start failure - program stopped")
-		return
+		mainLog.Error("Server start failure - program stopped", "err", err)
+		return exitStartFailure
+	}
+	server.TLS = tlsConfig
+
+	var service *rpc.RobotService
+	if *grpcPort != "" {
+		service, err = rpc.NewRobotService(application, log, tokens)
+		if err != nil {
+			mainLog.Error("gRPC service start failure - program stopped", "err", err)
+			return exitStartFailure
+		}
+		service.TLS = tlsConfig
+	}
+
+	// SIGINT and SIGTERM stop the whole program
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		mainLog.Info("Signal received, shutting down", "signal", sig.String())
+		cancel()
+	}()
+
+	// Car is halted as soon as the program is stopping, then servers are drained
+	haltErrs := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		haltErrs <- application.Stop(5 * time.Second)
+	}()
+
+	// Failure of any server stops the other one
+	rpcErrs := make(chan error, 1)
+	if service != nil {
+		go func() {
+			err := service.Start(ctx, *grpcPort)
+			if err != nil {
+				cancel()
+			}
+			rpcErrs <- err
+		}()
+	} else {
+		rpcErrs <- nil
 	}
-	server.Start(Port)
-	fmt.Println("Server started")
+
+	status := exitOK
+	if err := server.Start(ctx, Port); err != nil {
+		mainLog.Error("Server failure", "err", err)
+		status = exitServeFailure
+	}
+	cancel()
+
+	if err := <-rpcErrs; err != nil {
+		mainLog.Error("gRPC server failure", "err", err)
+		status = exitServeFailure
+	}
+
+	// Shutdown returns the result of the halt, it is already done
+	<-haltErrs
+	if err := application.Shutdown(5 * time.Second); err != nil {
+		mainLog.Error("Shutdown failure", "err", err)
+		return exitHaltFailure
+	}
+
+	mainLog.Info("Server stopped", "status", status)
+	return status
 }
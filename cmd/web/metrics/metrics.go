@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// collector is a metric, which can be written in Prometheus text format
+type collector interface {
+	write(w io.Writer)
+}
+
+// Registry stores all metrics of the Robot Server
+type Registry struct {
+	mutex      sync.Mutex
+	collectors []collector
+}
+
+// Default is a registry used by all subsystems
+var Default = &Registry{}
+
+func (registry *Registry) register(c collector) {
+	registry.mutex.Lock()
+	registry.collectors = append(registry.collectors, c)
+	registry.mutex.Unlock()
+}
+
+// Write writes all metrics in Prometheus text exposition format
+func (registry *Registry) Write(w io.Writer) {
+	registry.mutex.Lock()
+	collectors := append([]collector(nil), registry.collectors...)
+	registry.mutex.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// vec stores values of a metric split by a single label
+// Metric without label uses empty label value
+type vec struct {
+	mutex  sync.Mutex
+	name   string
+	help   string
+	label  string
+	values map[string]float64
+}
+
+func newVec(name string, help string, label string) vec {
+	return vec{name: name, help: help, label: label, values: make(map[string]float64)}
+}
+
+func (v *vec) labels(value string, extra string) string {
+	var pairs []string
+	if v.label != "" {
+		pairs = append(pairs, v.label+"="+strconv.Quote(value))
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	res := "{" + pairs[0]
+	for _, pair := range pairs[1:] {
+		res += "," + pair
+	}
+	return res + "}"
+}
+
+func (v *vec) write(w io.Writer, kind string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, kind)
+	if v.label == "" && len(v.values) == 0 {
+		fmt.Fprintf(w, "%s 0\n", v.name)
+		return
+	}
+	for _, value := range sortedKeys(v.values) {
+		fmt.Fprintf(w, "%s%s %s\n", v.name, v.labels(value, ""), formatFloat(v.values[value]))
+	}
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	vec
+}
+
+// NewCounter constructs Counter and registers it in Default registry
+// Label can be empty if counter is not split
+func NewCounter(name string, help string, label string) *Counter {
+	res := &Counter{newVec(name, help, label)}
+	Default.register(res)
+	return res
+}
+
+// Inc increments counter with the label value
+func (c *Counter) Inc(value string) {
+	c.Add(value, 1)
+}
+
+// Add increases counter with the label value
+func (c *Counter) Add(value string, delta float64) {
+	c.mutex.Lock()
+	c.values[value] += delta
+	c.mutex.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.vec.write(w, "counter")
+}
+
+// Gauge is a value, which can go up and down
+type Gauge struct {
+	vec
+}
+
+// NewGauge constructs Gauge and registers it in Default registry
+func NewGauge(name string, help string, label string) *Gauge {
+	res := &Gauge{newVec(name, help, label)}
+	Default.register(res)
+	return res
+}
+
+// Set changes gauge with the label value
+func (g *Gauge) Set(value string, v float64) {
+	g.mutex.Lock()
+	g.values[value] = v
+	g.mutex.Unlock()
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.vec.write(w, "gauge")
+}
+
+// DefaultBuckets are upper bounds of latency histograms in seconds
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// histogramData stores observations of a single label value
+type histogramData struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram counts observations in buckets
+type Histogram struct {
+	mutex   sync.Mutex
+	name    string
+	help    string
+	label   string
+	buckets []float64
+	data    map[string]*histogramData
+}
+
+// NewHistogram constructs Histogram and registers it in Default registry
+func NewHistogram(name string, help string, label string, buckets []float64) *Histogram {
+	res := &Histogram{name: name, help: help, label: label, buckets: buckets}
+	res.data = make(map[string]*histogramData)
+	Default.register(res)
+	return res
+}
+
+// Observe adds a single value
+func (h *Histogram) Observe(value string, v float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	data, ok := h.data[value]
+	if !ok {
+		data = &histogramData{counts: make([]uint64, len(h.buckets))}
+		h.data[value] = data
+	}
+
+	for i, bound := range h.buckets {
+		if v <= bound {
+			data.counts[i]++
+		}
+	}
+	data.count++
+	data.sum += v
+}
+
+// Since observes seconds elapsed from start
+func (h *Histogram) Since(value string, start time.Time) {
+	h.Observe(value, time.Since(start).Seconds())
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	labels := vec{label: h.label}
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+
+	values := make([]string, 0, len(h.data))
+	for value := range h.data {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		data := h.data[value]
+		for i, bound := range h.buckets {
+			le := "le=" + strconv.Quote(formatFloat(bound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labels.labels(value, le), data.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labels.labels(value, `le="+Inf"`), data.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labels.labels(value, ""), formatFloat(data.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labels.labels(value, ""), data.count)
+	}
+}
+
+func sortedKeys(values map[string]float64) []string {
+	res := make([]string, 0, len(values))
+	for key := range values {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,23 @@
+package ral
+
+import (
+	"github.com/RadiumByte/Robot-Server/cmd/web/metrics"
+)
+
+// Robot access metrics
+var (
+	commandsSent = metrics.NewCounter("robot_commands_sent_total",
+		"Commands sent to the car per type.", "type")
+	ralErrors = metrics.NewCounter("robot_ral_errors_total",
+		"Failed requests to the car.", "")
+	ralLatency = metrics.NewHistogram("robot_ral_roundtrip_seconds",
+		"Round-trip latency of requests to the car.", "", metrics.DefaultBuckets)
+)
+
+// commandType returns type of command for metrics, e.g. HALT, F, B or S
+func commandType(command string) string {
+	if command == "HALT" || len(command) == 0 {
+		return command
+	}
+	return command[:1]
+}
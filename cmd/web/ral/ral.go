@@ -1,19 +1,120 @@
 package ral
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
 	"github.com/valyala/fasthttp"
 )
 
+// DefaultTimeout limits a single request to the car
+const DefaultTimeout = time.Second
+
 // RoboCar represents Raspberry Pi based car
 type RoboCar struct {
+	// mutex protects Request and Response, shared by AI process and Web Server
+	mutex sync.Mutex
+
 	Client   *fasthttp.Client
 	Request  *fasthttp.Request
 	Response *fasthttp.Response
 	CarIP    string
 	CarPort  string
+	Log      *logger.Entry
+
+	// Scheme is "http" or "https"
+	Scheme string
+
+	// Timeout limits every attempt, so silent car can't block commands
+	Timeout time.Duration
+
+	// Health of the connection, protected by mutex
+	lastSuccess time.Time
+	failures    int
+}
+
+// send performs request to the Car and collects metrics
+func (robot *RoboCar) send(command string) error {
+	robot.mutex.Lock()
+	defer robot.mutex.Unlock()
+
+	robot.Request.SetRequestURI(robot.url(command))
+
+	start := time.Now()
+	err := robot.Client.DoTimeout(robot.Request, robot.Response, robot.Timeout)
+	ralLatency.Since("", start)
+	commandsSent.Inc(commandType(command))
+
+	if err == nil && robot.Response.StatusCode() != fasthttp.StatusOK {
+		err = fmt.Errorf("car responded with status %d", robot.Response.StatusCode())
+	}
+
+	if err != nil {
+		ralErrors.Inc("")
+		robot.failures++
+		robot.Log.Error("Request to the car failed", "command", command, "err", err)
+	} else {
+		robot.failures = 0
+		robot.lastSuccess = time.Now()
+	}
+	return err
+}
+
+// url builds address of the command on the Car
+func (robot *RoboCar) url(command string) string {
+	return robot.Scheme + "://" + robot.CarIP + robot.CarPort + "/" + command
+}
+
+// UseTLS switches connection to HTTPS
+// Car's certificate is trusted only if it is signed by CA from caFile, system roots are ignored
+func (robot *RoboCar) UseTLS(caFile string, serverName string) error {
+	body, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(body) {
+		return errors.New(caFile + ": no PEM certificates found")
+	}
+
+	if serverName == "" {
+		serverName = robot.CarIP
+	}
+
+	robot.mutex.Lock()
+	defer robot.mutex.Unlock()
+
+	robot.Client.TLSConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+		ServerName: serverName,
+	}
+	robot.Scheme = "https"
+	return nil
+}
+
+// LastSuccess returns time of the latest confirmed command
+func (robot *RoboCar) LastSuccess() time.Time {
+	robot.mutex.Lock()
+	defer robot.mutex.Unlock()
+
+	return robot.lastSuccess
+}
+
+// ConsecutiveFailures returns number of failed commands in a row
+func (robot *RoboCar) ConsecutiveFailures() int {
+	robot.mutex.Lock()
+	defer robot.mutex.Unlock()
+
+	return robot.failures
 }
 
 // Turn sends only steering command to Car
@@ -26,13 +127,8 @@ func (robot *RoboCar) Turn(steerValue int) {
 
 	steerValueStr := strconv.Itoa(steerValue)
 	command := "S" + steerValueStr + "A"
-	//fmt.Println("Sending command: " + command)
-
-	url := "http://" + robot.CarIP + robot.CarPort + "/" + command
-	robot.Request.SetRequestURI(url)
-	robot.Client.Do(robot.Request, robot.Response)
-
-	//fmt.Println("Command sent to robot: " + command)
+	robot.Log.Debug("Sending command", "command", command)
+	robot.send(command)
 }
 
 // DirectCommand sends any command, according to Car's specs
@@ -41,19 +137,52 @@ func (robot *RoboCar) DirectCommand(command string) {
 		command += "A"
 	}
 
-	fmt.Println("Sending command: " + command)
+	robot.Log.Debug("Sending command", "command", command)
+
+	robot.send(command)
+
+	robot.Log.Debug("Command sent to robot", "command", command)
+}
+
+// Halt stops the car, command is repeated until the car confirms it
+// Every attempt is limited by Timeout
+func (robot *RoboCar) Halt(attempts int) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = robot.send("HALT"); err == nil {
+			return nil
+		}
+		time.Sleep(time.Duration(i+1) * 100 * time.Millisecond)
+	}
+	return fmt.Errorf("HALT failed after %d attempts: %v", attempts, err)
+}
+
+// Probe checks that the car answers HTTP requests without sending any command
+func (robot *RoboCar) Probe() error {
+	request := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(request)
+	response := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(response)
 
-	url := "http://" + robot.CarIP + robot.CarPort + "/" + command
-	robot.Request.SetRequestURI(url)
-	robot.Client.Do(robot.Request, robot.Response)
+	request.SetRequestURI(robot.url(""))
+	request.Header.SetMethod("GET")
 
-	fmt.Println("Command sent to robot: " + command)
+	start := time.Now()
+	err := robot.Client.DoTimeout(request, response, robot.Timeout)
+	ralLatency.Since("", start)
+	if err != nil {
+		ralErrors.Inc("")
+		return fmt.Errorf("car does not answer: %v", err)
+	}
+	return nil
 }
 
 // NewRoboCar constructs object of RoboCar
-func NewRoboCar(ip string, port string) (*RoboCar, error) {
+func NewRoboCar(ip string, port string, log *logger.Logger) (*RoboCar, error) {
 	res := &RoboCar{}
-	res.Client = &fasthttp.Client{}
+	res.Log = log.Component("ral")
+	res.Timeout = DefaultTimeout
+	res.Client = &fasthttp.Client{ReadTimeout: res.Timeout, WriteTimeout: res.Timeout}
 	res.Request = fasthttp.AcquireRequest()
 	res.Request.Header.SetMethod("PUT")
 
@@ -61,5 +190,6 @@ func NewRoboCar(ip string, port string) (*RoboCar, error) {
 
 	res.CarPort = port
 	res.CarIP = ip
+	res.Scheme = "http"
 	return res, nil
 }
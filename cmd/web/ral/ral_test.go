@@ -0,0 +1,96 @@
+package ral
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
+	"github.com/RadiumByte/Robot-Server/cmd/web/testcert"
+)
+
+// newCarServer starts HTTPS server with certificate issued by CA, it answers every command
+func newCarServer(t *testing.T, ca *testcert.CA) *httptest.Server {
+	leaf, err := ca.Issue("car")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := leaf.TLSCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	res.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	res.StartTLS()
+	return res
+}
+
+// newPinnedCar constructs RoboCar, which trusts only the CA
+func newPinnedCar(t *testing.T, server *httptest.Server, ca *testcert.CA) *RoboCar {
+	log, err := logger.New(ioutil.Discard, logger.FormatLogfmt, logger.ErrorLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	robot, err := NewRoboCar(host, ":"+port, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "robot-ral")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile, err := testcert.WriteFile(dir, "ca.pem", ca.PEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := robot.UseTLS(caFile, ""); err != nil {
+		t.Fatal(err)
+	}
+	return robot
+}
+
+func TestUseTLSTrustsPinnedCA(t *testing.T) {
+	ca, err := testcert.NewCA("car")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newCarServer(t, ca)
+	defer server.Close()
+
+	robot := newPinnedCar(t, server, ca)
+	if err := robot.send("HALT"); err != nil {
+		t.Fatalf("car with certificate of pinned CA is rejected: %v", err)
+	}
+}
+
+func TestUseTLSRejectsUnknownServer(t *testing.T) {
+	ca, err := testcert.NewCA("car")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := testcert.NewCA("impostor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newCarServer(t, other)
+	defer server.Close()
+
+	robot := newPinnedCar(t, server, ca)
+	if err := robot.send("HALT"); err == nil {
+		t.Fatal("car with certificate of unknown CA is accepted")
+	}
+	if robot.ConsecutiveFailures() != 1 {
+		t.Fatalf("failure is not counted: %d", robot.ConsecutiveFailures())
+	}
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodRoles lists roles required by methods, other methods require operator
+var methodRoles = map[string]string{
+	"/robot.v1.RobotControl/GetStatus":        api.RoleViewer,
+	"/robot.v1.RobotControl/StreamTelemetry":  api.RoleViewer,
+	"/robot.v1.RobotControl/StreamDetections": api.RoleViewer,
+}
+
+// roleOf returns role required by the method
+func roleOf(method string) string {
+	if role, ok := methodRoles[method]; ok {
+		return role
+	}
+	return api.RoleOperator
+}
+
+// principalKey is a key of the principal in context of the call
+type principalKey struct{}
+
+// principalOf returns principal, stored by authorize
+func principalOf(ctx context.Context) *api.Principal {
+	if principal, ok := ctx.Value(principalKey{}).(*api.Principal); ok {
+		return principal
+	}
+	return api.Anonymous
+}
+
+// remoteOf returns address of the client
+func remoteOf(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// authorize checks token from "authorization" metadata and stores principal in context
+func (service *RobotService) authorize(ctx context.Context, method string) (context.Context, error) {
+	principal := api.Anonymous
+	if service.tokens != nil {
+		var token string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				token = strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+			}
+		}
+		if token == "" {
+			authFailures.Inc("unauthenticated")
+			return nil, status.Error(codes.Unauthenticated, "authentication token is required")
+		}
+
+		var ok bool
+		principal, ok = service.tokens.Lookup(token)
+		if !ok {
+			authFailures.Inc("unauthenticated")
+			return nil, status.Error(codes.Unauthenticated, "authentication token is invalid")
+		}
+	}
+
+	role := roleOf(method)
+	if !principal.Allows(role) {
+		authFailures.Inc("forbidden")
+		service.audit.Warn("Access denied", "principal", principal.Name, "role", principal.Role,
+			"required", role, "method", method, "remote", remoteOf(ctx))
+		return nil, status.Error(codes.PermissionDenied, "role "+role+" is required")
+	}
+
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+// unaryAuth authorizes unary calls
+func (service *RobotService) unaryAuth(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := service.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authorizedStream replaces context of the stream
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authorizedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// streamAuth authorizes streaming calls
+func (service *RobotService) streamAuth(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := service.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,11 @@
+package rpc
+
+import (
+	"github.com/RadiumByte/Robot-Server/cmd/web/metrics"
+)
+
+// gRPC service metrics
+var (
+	authFailures = metrics.NewCounter("robot_grpc_auth_failures_total",
+		"Rejected gRPC calls per reason.", "reason")
+)
@@ -0,0 +1,431 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"image"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RadiumByte/Robot-Server/cmd/web/api"
+	"github.com/RadiumByte/Robot-Server/cmd/web/app"
+	"github.com/RadiumByte/Robot-Server/cmd/web/logger"
+	"github.com/RadiumByte/Robot-Server/pkg/robotpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Limits of telemetry stream interval
+const (
+	defaultTelemetryInterval = 200 * time.Millisecond
+	minTelemetryInterval     = 20 * time.Millisecond
+)
+
+// RobotService provides gRPC access to the Robot Server
+type RobotService struct {
+	robotpb.UnimplementedRobotControlServer
+
+	application app.RobotServer
+	tokens      *api.TokenStore
+	log         *logger.Entry
+	audit       *logger.Entry
+
+	// TLS enables encryption, nil means plaintext
+	TLS *tls.Config
+}
+
+// signCommands maps signs to commands of the application
+var signCommands = map[robotpb.Sign]string{
+	robotpb.Sign_SIGN_STOP:   "stopsign",
+	robotpb.Sign_SIGN_CIRCLE: "circlesign",
+	robotpb.Sign_SIGN_YIELD:  "yieldsign",
+}
+
+// signOf returns sign of the cascade
+func signOf(cascade int) robotpb.Sign {
+	switch cascade {
+	case app.StopCascade:
+		return robotpb.Sign_SIGN_STOP
+	case app.CircleCascade:
+		return robotpb.Sign_SIGN_CIRCLE
+	case app.YieldCascade:
+		return robotpb.Sign_SIGN_YIELD
+	}
+	return robotpb.Sign_SIGN_UNSPECIFIED
+}
+
+// signOfClass returns sign of the class name, e.g. "stop"
+func signOfClass(class string) robotpb.Sign {
+	if sign, ok := robotpb.Sign_value["SIGN_"+strings.ToUpper(class)]; ok {
+		return robotpb.Sign(sign)
+	}
+	return robotpb.Sign_SIGN_UNSPECIFIED
+}
+
+// modeOf returns driving mode
+func modeOf(manual bool) robotpb.Mode {
+	if manual {
+		return robotpb.Mode_MODE_MANUAL
+	}
+	return robotpb.Mode_MODE_AUTO
+}
+
+// overlayOf returns overlay mode
+func overlayOf(overlay int) robotpb.Overlay {
+	if overlay == app.OverlayFunnel {
+		return robotpb.Overlay_OVERLAY_FUNNEL
+	}
+	return robotpb.Overlay_OVERLAY_TARGET
+}
+
+// rectOf converts rectangle
+func rectOf(rect image.Rectangle) *robotpb.Rect {
+	return &robotpb.Rect{
+		MinX: int32(rect.Min.X),
+		MinY: int32(rect.Min.Y),
+		MaxX: int32(rect.Max.X),
+		MaxY: int32(rect.Max.Y),
+	}
+}
+
+// rectsOf converts list of rectangles
+func rectsOf(rects []image.Rectangle) []*robotpb.Rect {
+	res := make([]*robotpb.Rect, 0, len(rects))
+	for _, rect := range rects {
+		res = append(res, rectOf(rect))
+	}
+	return res
+}
+
+// telemetryOf converts telemetry
+func telemetryOf(telemetry app.Telemetry) *robotpb.Telemetry {
+	res := &robotpb.Telemetry{
+		Throttle:       int32(telemetry.Throttle),
+		Steering:       int32(telemetry.Steering),
+		Fps:            telemetry.FPS,
+		FailureCounter: int32(telemetry.FailureCounter),
+		HasTarget:      telemetry.HasTarget,
+	}
+	if !telemetry.Updated.IsZero() {
+		res.Updated = timestamppb.New(telemetry.Updated)
+	}
+	return res
+}
+
+// detectionOf converts frame event
+func detectionOf(event app.Event, frame app.FrameEvent) *robotpb.Detection {
+	res := &robotpb.Detection{
+		Frame:     frame.Frame,
+		Seq:       frame.Seq,
+		Sign:      signOfClass(frame.Class),
+		Raw:       rectsOf(frame.Raw),
+		Near:      rectsOf(frame.Near),
+		Throttle:  int32(frame.Throttle),
+		Steering:  int32(frame.Steering),
+		Failures:  int32(frame.Failures),
+		Processed: durationpb.New(frame.Processed),
+		Time:      timestamppb.New(event.Time),
+	}
+	for _, scored := range frame.Scored {
+		res.Scored = append(res.Scored, &robotpb.ScoredRect{
+			Rect:    rectOf(scored.Rect),
+			Score:   scored.Score,
+			Trusted: scored.Trusted,
+		})
+	}
+	if frame.Target != nil {
+		res.Target = rectOf(*frame.Target)
+	}
+	return res
+}
+
+// execute runs commands of the application on behalf of the caller
+func (service *RobotService) execute(ctx context.Context, commands ...string) (*robotpb.CommandReply, error) {
+	principal := principalOf(ctx)
+	remote := remoteOf(ctx)
+
+	for _, command := range commands {
+		if err := service.application.ProcessCommand(command); err != nil {
+			service.audit.Info("Command rejected", "principal", principal.Name, "command", command,
+				"remote", remote, "transport", "grpc", "err", err)
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		service.audit.Info("Command accepted", "principal", principal.Name, "command", command,
+			"remote", remote, "transport", "grpc")
+	}
+	return &robotpb.CommandReply{Commands: commands}, nil
+}
+
+// drive applies commands of a joystick sample without auditing every one of them
+// Samples arrive many times per second, Joystick audits the stream and rejected samples
+func (service *RobotService) drive(commands ...string) error {
+	for _, command := range commands {
+		if err := service.application.ProcessCommand(command); err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	return nil
+}
+
+// driveCommands validates drive request and converts it to commands
+func driveCommands(req *robotpb.DriveRequest) ([]string, error) {
+	var res []string
+	if req.Steering != nil {
+		steering := req.GetSteering()
+		if steering < 0 || steering > 100 {
+			return nil, status.Error(codes.InvalidArgument, "steering must be in 0..100")
+		}
+		res = append(res, "S"+strconv.Itoa(int(steering)))
+	}
+	if req.Throttle != nil {
+		throttle := req.GetThrottle()
+		if throttle < -100 || throttle > 100 {
+			return nil, status.Error(codes.InvalidArgument, "throttle must be in -100..100")
+		}
+		if throttle < 0 {
+			res = append(res, "B"+strconv.Itoa(int(-throttle)))
+		} else {
+			res = append(res, "F"+strconv.Itoa(int(throttle)))
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "steering or throttle is required")
+	}
+	return res, nil
+}
+
+// GetStatus returns current state of the application
+func (service *RobotService) GetStatus(ctx context.Context, req *robotpb.GetStatusRequest) (*robotpb.Status, error) {
+	state := service.application.Status()
+
+	res := &robotpb.Status{
+		Mode:    modeOf(state.IsManual),
+		Blocked: state.IsBlocked,
+		Sign:    signOf(state.CascadeType),
+		Overlay: overlayOf(state.Overlay),
+		Acquisition: &robotpb.Acquisition{
+			State:     state.Acquisition.State,
+			Confirmed: int32(state.Acquisition.Confirmed),
+			Required:  int32(state.Acquisition.Required),
+		},
+		Telemetry: telemetryOf(state.Telemetry),
+	}
+	if state.Preflight != nil {
+		passed := state.Preflight.Passed
+		res.PreflightPassed = &passed
+	}
+	return res, nil
+}
+
+// SetMode switches between manual and automatic driving
+func (service *RobotService) SetMode(ctx context.Context, req *robotpb.SetModeRequest) (*robotpb.CommandReply, error) {
+	switch req.Mode {
+	case robotpb.Mode_MODE_MANUAL:
+		return service.execute(ctx, "manual")
+	case robotpb.Mode_MODE_AUTO:
+		return service.execute(ctx, "auto")
+	}
+	return nil, status.Error(codes.InvalidArgument, "mode is required")
+}
+
+// SetBlocked halts the car or allows it to move
+func (service *RobotService) SetBlocked(ctx context.Context, req *robotpb.SetBlockedRequest) (*robotpb.CommandReply, error) {
+	if req.Blocked {
+		return service.execute(ctx, "halt")
+	}
+	return service.execute(ctx, "go")
+}
+
+// SetSign selects traffic sign to follow
+func (service *RobotService) SetSign(ctx context.Context, req *robotpb.SetSignRequest) (*robotpb.CommandReply, error) {
+	command, ok := signCommands[req.Sign]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "sign is required")
+	}
+	return service.execute(ctx, command)
+}
+
+// SetOverlay selects overlay of the video
+func (service *RobotService) SetOverlay(ctx context.Context, req *robotpb.SetOverlayRequest) (*robotpb.CommandReply, error) {
+	switch req.Overlay {
+	case robotpb.Overlay_OVERLAY_TARGET:
+		return service.execute(ctx, "debugoff")
+	case robotpb.Overlay_OVERLAY_FUNNEL:
+		return service.execute(ctx, "debugon")
+	}
+	return nil, status.Error(codes.InvalidArgument, "overlay is required")
+}
+
+// Drive sends manual steering and throttle
+func (service *RobotService) Drive(ctx context.Context, req *robotpb.DriveRequest) (*robotpb.CommandReply, error) {
+	commands, err := driveCommands(req)
+	if err != nil {
+		return nil, err
+	}
+	return service.execute(ctx, commands...)
+}
+
+// StreamTelemetry sends driving state periodically
+func (service *RobotService) StreamTelemetry(req *robotpb.StreamTelemetryRequest,
+	stream robotpb.RobotControl_StreamTelemetryServer) error {
+	interval := defaultTelemetryInterval
+	if req.Interval != nil {
+		interval = req.Interval.AsDuration()
+	}
+	if interval < minTelemetryInterval {
+		interval = minTelemetryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := stream.Send(telemetryOf(service.application.Status().Telemetry)); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamDetections sends detections of every processed frame
+func (service *RobotService) StreamDetections(req *robotpb.StreamDetectionsRequest,
+	stream robotpb.RobotControl_StreamDetectionsServer) error {
+	events := service.application.Events()
+	sub := events.Subscribe([]string{app.EventFrame})
+	defer events.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			frame, ok := event.Data.(app.FrameEvent)
+			if !ok {
+				continue
+			}
+			if err := stream.Send(detectionOf(event, frame)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Joystick applies every drive request of the stream and acknowledges it
+// Car stops, when the stream ends or breaks
+func (service *RobotService) Joystick(stream robotpb.RobotControl_JoystickServer) error {
+	ctx := stream.Context()
+	principal := principalOf(ctx)
+	remote := remoteOf(ctx)
+	moving := false
+	samples, rejected := 0, 0
+
+	service.audit.Info("Joystick opened", "principal", principal.Name, "remote", remote, "transport", "grpc")
+	defer func() {
+		stopped := moving && service.drive("F0") == nil
+		service.audit.Info("Joystick closed", "principal", principal.Name, "remote", remote, "transport", "grpc",
+			"samples", samples, "rejected", rejected, "stopped", stopped)
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		samples++
+
+		ack := &robotpb.DriveAck{Seq: req.Seq, Accepted: true}
+		commands, err := driveCommands(req)
+		if err == nil {
+			err = service.drive(commands...)
+		}
+		if err != nil {
+			rejected++
+			service.audit.Info("Command rejected", "principal", principal.Name, "command", strings.Join(commands, ","),
+				"remote", remote, "transport", "grpc", "seq", req.Seq, "err", status.Convert(err).Message())
+			ack.Accepted = false
+			ack.Error = status.Convert(err).Message()
+		} else if req.Throttle != nil {
+			moving = req.GetThrottle() != 0
+		}
+
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
+// Start serves gRPC until context is cancelled or server fails
+func (service *RobotService) Start(ctx context.Context, port string) error {
+	listener, err := net.Listen("tcp4", port)
+	if err != nil {
+		return err
+	}
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(service.unaryAuth),
+		grpc.StreamInterceptor(service.streamAuth),
+	}
+	if service.TLS != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(service.TLS)))
+	}
+
+	server := grpc.NewServer(options...)
+	robotpb.RegisterRobotControlServer(server, service)
+
+	errs := make(chan error, 1)
+	go func() {
+		service.log.Info("gRPC server is starting", "port", port, "tls", service.TLS != nil)
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Streams never finish by themselves, so they are cut after a grace period
+	service.log.Info("gRPC server is shutting down")
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		server.Stop()
+	}
+	return nil
+}
+
+// NewRobotService constructs gRPC service
+// Nil tokens disable authentication, every client is an operator then
+func NewRobotService(application app.RobotServer, log *logger.Logger, tokens *api.TokenStore) (*RobotService, error) {
+	res := &RobotService{}
+	res.application = application
+	res.tokens = tokens
+	res.log = log.Component("grpc")
+	res.audit = log.Component("audit")
+
+	return res, nil
+}
@@ -9,4 +9,6 @@ require (
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasthttp v0.0.0-20180831062205-5f6439b6df1c
 	gocv.io/x/gocv v0.20.0
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 )
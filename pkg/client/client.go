@@ -0,0 +1,587 @@
+// Package client is a typed client of the Robot Server HTTP API
+// Operations of the client are checked against /openapi.json by VerifySpec
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commands of the application
+const (
+	CommandHalt       = "halt"
+	CommandGo         = "go"
+	CommandManual     = "manual"
+	CommandAuto       = "auto"
+	CommandStopSign   = "stopsign"
+	CommandCircleSign = "circlesign"
+	CommandYieldSign  = "yieldsign"
+	CommandDebugOn    = "debugon"
+	CommandDebugOff   = "debugoff"
+)
+
+// Signs are classes of traffic signs in order of cascade numbers
+var Signs = []string{"stop", "circle", "yield"}
+
+// Overlays are names of overlay modes in order of their numbers
+var Overlays = []string{"target", "funnel"}
+
+// Options configures Client
+type Options struct {
+	// Token is sent as bearer token, empty token is not sent
+	Token string
+
+	// TLS configures HTTPS, e.g. pinned CA and client certificate for mutual TLS
+	TLS *tls.Config
+
+	// Timeout limits short requests, streams are not limited
+	Timeout time.Duration
+}
+
+// Client performs requests to the Robot Server API
+type Client struct {
+	BaseURL string
+	Token   string
+	TLS     *tls.Config
+	Timeout time.Duration
+	HTTP    *http.Client
+}
+
+// New constructs Client of the server, e.g. https://10.0.0.2:8080
+func New(baseURL string, opts Options) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, errors.New("server URL must be http or https: " + baseURL)
+	}
+
+	res := &Client{}
+	res.BaseURL = strings.TrimRight(baseURL, "/")
+	res.Token = opts.Token
+	res.TLS = opts.TLS
+	res.Timeout = opts.Timeout
+	if res.Timeout == 0 {
+		res.Timeout = 10 * time.Second
+	}
+	res.HTTP = &http.Client{Transport: &http.Transport{TLSClientConfig: opts.TLS}}
+	return res, nil
+}
+
+// APIError is a non-successful response of the server
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("server responded %d: %s", err.Status, err.Message)
+}
+
+// open performs request and returns response
+// Response with unexpected status is returned as APIError
+// Caller must close body of the response
+func (client *Client) open(ctx context.Context, method string, path string, payload []byte, accept ...int) (*http.Response, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequest(method, client.BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request = request.WithContext(ctx)
+	if client.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+client.Token)
+	}
+
+	response, err := client.HTTP.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode/100 == 2 {
+		return response, nil
+	}
+	for _, status := range accept {
+		if response.StatusCode == status {
+			return response, nil
+		}
+	}
+
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 4096))
+	return nil, &APIError{Status: response.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+// do performs short request and returns its body
+func (client *Client) do(ctx context.Context, method string, path string, accept ...int) ([]byte, int, error) {
+	return client.doBody(ctx, method, path, nil, accept...)
+}
+
+// doBody performs short request with JSON body and returns body of the response
+func (client *Client) doBody(ctx context.Context, method string, path string, request []byte, accept ...int) ([]byte, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	response, err := client.open(ctx, method, path, request, accept...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	return body, response.StatusCode, err
+}
+
+// getJSON decodes response of GET request
+func (client *Client) getJSON(ctx context.Context, path string, res interface{}, accept ...int) (int, error) {
+	body, status, err := client.do(ctx, "GET", path, accept...)
+	if err != nil {
+		return status, err
+	}
+	return status, json.Unmarshal(body, res)
+}
+
+// postJSON sends request as JSON and decodes response of POST request
+func (client *Client) postJSON(ctx context.Context, path string, request interface{}, res interface{}) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	body, _, err := client.doBody(ctx, "POST", path, payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, res)
+}
+
+// Command sends a command to the application, e.g. CommandHalt or "S50"
+func (client *Client) Command(ctx context.Context, command string) error {
+	_, _, err := client.do(ctx, "PUT", "/"+url.PathEscape(command))
+	return err
+}
+
+// Halt stops the car and blocks it
+func (client *Client) Halt(ctx context.Context) error {
+	return client.Command(ctx, CommandHalt)
+}
+
+// Go unblocks the car
+func (client *Client) Go(ctx context.Context) error {
+	return client.Command(ctx, CommandGo)
+}
+
+// SetManual switches between manual and automatic driving
+func (client *Client) SetManual(ctx context.Context, manual bool) error {
+	if manual {
+		return client.Command(ctx, CommandManual)
+	}
+	return client.Command(ctx, CommandAuto)
+}
+
+// SetSign selects traffic sign to follow, one of Signs
+func (client *Client) SetSign(ctx context.Context, sign string) error {
+	for _, known := range Signs {
+		if sign == known {
+			return client.Command(ctx, sign+"sign")
+		}
+	}
+	return errors.New("unknown sign: " + sign)
+}
+
+// SetOverlay selects overlay of the video, one of Overlays
+func (client *Client) SetOverlay(ctx context.Context, overlay string) error {
+	switch overlay {
+	case "target":
+		return client.Command(ctx, CommandDebugOff)
+	case "funnel":
+		return client.Command(ctx, CommandDebugOn)
+	}
+	return errors.New("unknown overlay: " + overlay)
+}
+
+// Steer turns wheels on manual control, 0..100, 50 is straight
+func (client *Client) Steer(ctx context.Context, value int) error {
+	if value < 0 || value > 100 {
+		return errors.New("steering must be in 0..100")
+	}
+	return client.Command(ctx, "S"+strconv.Itoa(value))
+}
+
+// Throttle drives on manual control, -100..100, negative is backward
+func (client *Client) Throttle(ctx context.Context, value int) error {
+	if value < -100 || value > 100 {
+		return errors.New("throttle must be in -100..100")
+	}
+	if value < 0 {
+		return client.Command(ctx, "B"+strconv.Itoa(-value))
+	}
+	return client.Command(ctx, "F"+strconv.Itoa(value))
+}
+
+// Status returns current state of the application
+func (client *Client) Status(ctx context.Context) (*Status, error) {
+	res := &Status{}
+	if _, err := client.getJSON(ctx, "/status", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Preflight runs self-test, failed self-test is not an error
+func (client *Client) Preflight(ctx context.Context) (*PreflightReport, error) {
+	res := &PreflightReport{}
+	if _, err := client.getJSON(ctx, "/preflight", res, http.StatusServiceUnavailable); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Healthz returns liveness report, unhealthy server is not an error
+func (client *Client) Healthz(ctx context.Context) (*HealthReport, error) {
+	res := &HealthReport{}
+	if _, err := client.getJSON(ctx, "/healthz", res, http.StatusServiceUnavailable); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Readyz returns readiness report, server, which is not ready, is not an error
+func (client *Client) Readyz(ctx context.Context) (*HealthReport, error) {
+	res := &HealthReport{}
+	if _, err := client.getJSON(ctx, "/readyz", res, http.StatusServiceUnavailable); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LogLevels returns log verbosity of every component
+func (client *Client) LogLevels(ctx context.Context) (map[string]string, error) {
+	res := make(map[string]string)
+	if _, err := client.getJSON(ctx, "/log", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetLogLevel changes log verbosity, component "default" changes components without explicit verbosity
+func (client *Client) SetLogLevel(ctx context.Context, component string, level string) error {
+	_, _, err := client.do(ctx, "POST", "/log/"+url.PathEscape(component)+"/"+url.PathEscape(level))
+	return err
+}
+
+// Metrics returns metrics in Prometheus text format
+func (client *Client) Metrics(ctx context.Context) (string, error) {
+	body, _, err := client.do(ctx, "GET", "/metrics")
+	return string(body), err
+}
+
+// Snapshot returns the latest processed frame
+func (client *Client) Snapshot(ctx context.Context, opts SnapshotOptions) ([]byte, error) {
+	query := url.Values{}
+	if opts.Raw {
+		query.Set("kind", "raw")
+	}
+	if opts.PNG {
+		query.Set("format", "png")
+	}
+	if opts.Width > 0 {
+		query.Set("width", strconv.Itoa(opts.Width))
+	}
+	if opts.Height > 0 {
+		query.Set("height", strconv.Itoa(opts.Height))
+	}
+
+	path := "/api/v1/snapshot"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	body, _, err := client.do(ctx, "GET", path)
+	return body, err
+}
+
+// StartCapture saves the next frames with their detections on the server
+// Format is "png" or "jpeg", empty format means PNG
+func (client *Client) StartCapture(ctx context.Context, frames int, format string) (*CaptureStatus, error) {
+	request, err := json.Marshal(captureRequest{Frames: frames, Format: format})
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := client.doBody(ctx, "POST", "/api/v1/captures", request)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CaptureStatus{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Captures returns progress of recent capture jobs
+func (client *Client) Captures(ctx context.Context) ([]CaptureStatus, error) {
+	var res []CaptureStatus
+	if _, err := client.getJSON(ctx, "/api/v1/captures", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Recording returns state of the current or the last video recording
+func (client *Client) Recording(ctx context.Context) (*RecordingStatus, error) {
+	res := &RecordingStatus{}
+	if _, err := client.getJSON(ctx, "/api/v1/recording", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// StartRecording records annotated autopilot view to video files on the server
+func (client *Client) StartRecording(ctx context.Context, opts RecordingOptions) (*RecordingStatus, error) {
+	request, err := json.Marshal(recordingRequest{MaxDuration: int(opts.MaxDuration.Seconds()), FPS: opts.FPS})
+	if err != nil {
+		return nil, err
+	}
+	return client.recordingCommand(ctx, "/api/v1/recording/start", request)
+}
+
+// StopRecording stops video recording
+func (client *Client) StopRecording(ctx context.Context) (*RecordingStatus, error) {
+	return client.recordingCommand(ctx, "/api/v1/recording/stop", nil)
+}
+
+// recordingCommand posts request and decodes state of the recording
+func (client *Client) recordingCommand(ctx context.Context, path string, request []byte) (*RecordingStatus, error) {
+	body, _, err := client.doBody(ctx, "POST", path, request)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RecordingStatus{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Camera returns capture properties reported by the camera and its saved profile
+func (client *Client) Camera(ctx context.Context) (*CameraState, error) {
+	res := &CameraState{}
+	if _, err := client.getJSON(ctx, "/api/v1/camera", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetCamera changes capture properties, e.g. "exposure" or "width"
+// Values rejected by the camera are not an error, they are reported by Applied of the result
+func (client *Client) SetCamera(ctx context.Context, properties map[string]float64) (*CameraUpdate, error) {
+	request, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := client.doBody(ctx, "POST", "/api/v1/camera", request, http.StatusUnprocessableEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CameraUpdate{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Preprocess returns preprocessing steps applied before detection
+func (client *Client) Preprocess(ctx context.Context) (*PreprocessParams, error) {
+	res := &PreprocessParams{}
+	if _, err := client.getJSON(ctx, "/api/v1/preprocess", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetPreprocess changes preprocessing steps and returns all of them
+// Patch is merged into current steps, e.g. map[string]interface{}{"gamma": map[string]interface{}{"enabled": true}}
+func (client *Client) SetPreprocess(ctx context.Context, patch interface{}) (*PreprocessParams, error) {
+	res := &PreprocessParams{}
+	if err := client.postJSON(ctx, "/api/v1/preprocess", patch, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Masks returns include and exclude zones of the frame
+func (client *Client) Masks(ctx context.Context) (*Masks, error) {
+	res := &Masks{}
+	if _, err := client.getJSON(ctx, "/api/v1/masks", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetMasks replaces all zones, empty Masks removes them
+func (client *Client) SetMasks(ctx context.Context, masks Masks) (*Masks, error) {
+	if masks.Include == nil {
+		masks.Include = []Zone{}
+	}
+	if masks.Exclude == nil {
+		masks.Exclude = []Zone{}
+	}
+	request, err := json.Marshal(masks)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := client.doBody(ctx, "POST", "/api/v1/masks", request)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Masks{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Quality returns thresholds of the frame quality gate
+func (client *Client) Quality(ctx context.Context) (*QualityParams, error) {
+	res := &QualityParams{}
+	if _, err := client.getJSON(ctx, "/api/v1/quality", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetQuality changes thresholds of the frame quality gate and returns all of them
+// Patch is merged into current thresholds, e.g. map[string]interface{}{"min_sharpness": 40}
+func (client *Client) SetQuality(ctx context.Context, patch interface{}) (*QualityParams, error) {
+	res := &QualityParams{}
+	if err := client.postJSON(ctx, "/api/v1/quality", patch, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Acquisition returns confirmation window of the target
+func (client *Client) Acquisition(ctx context.Context) (*AcquisitionParams, error) {
+	res := &AcquisitionParams{}
+	if _, err := client.getJSON(ctx, "/api/v1/acquisition", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetAcquisition changes confirmation window and returns all of its parameters
+// Patch is merged into current parameters, e.g. map[string]interface{}{"required": 2}
+func (client *Client) SetAcquisition(ctx context.Context, patch interface{}) (*AcquisitionParams, error) {
+	res := &AcquisitionParams{}
+	if err := client.postJSON(ctx, "/api/v1/acquisition", patch, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Search returns parameters of the search window around the previous target
+func (client *Client) Search(ctx context.Context) (*SearchParams, error) {
+	res := &SearchParams{}
+	if _, err := client.getJSON(ctx, "/api/v1/search", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetSearch changes parameters of the search window and returns all of them
+// Patch is merged into current parameters, e.g. map[string]interface{}{"enabled": true}
+func (client *Client) SetSearch(ctx context.Context, patch interface{}) (*SearchParams, error) {
+	res := &SearchParams{}
+	if err := client.postJSON(ctx, "/api/v1/search", patch, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Shape returns contour geometry thresholds of the signs
+func (client *Client) Shape(ctx context.Context) (*ShapeThresholds, error) {
+	res := &ShapeThresholds{}
+	if _, err := client.getJSON(ctx, "/api/v1/shape", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetShape changes contour geometry thresholds and returns all of them
+// Patch is merged into current thresholds, e.g. map[string]interface{}{"stop": map[string]interface{}{"enabled": false}}
+func (client *Client) SetShape(ctx context.Context, patch interface{}) (*ShapeThresholds, error) {
+	res := &ShapeThresholds{}
+	if err := client.postJSON(ctx, "/api/v1/shape", patch, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Grouping returns params of merging of overlapping cascade hits
+func (client *Client) Grouping(ctx context.Context) (*GroupingParams, error) {
+	res := &GroupingParams{}
+	if _, err := client.getJSON(ctx, "/api/v1/grouping", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetGrouping changes merging of cascade hits and returns all of its params
+// Patch is merged into current params, e.g. map[string]interface{}{"use_group_rectangles": true}
+func (client *Client) SetGrouping(ctx context.Context, patch interface{}) (*GroupingParams, error) {
+	res := &GroupingParams{}
+	if err := client.postJSON(ctx, "/api/v1/grouping", patch, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Frame returns a single JPEG frame of the autopilot view
+func (client *Client) Frame(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	response, err := client.open(ctx, "GET", "/video", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	_, params, err := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if err != nil || params["boundary"] == "" {
+		return nil, errors.New("video stream has no multipart boundary")
+	}
+
+	part, err := multipart.NewReader(response.Body, params["boundary"]).NextPart()
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(part)
+}
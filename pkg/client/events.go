@@ -0,0 +1,249 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Minimal RFC 6455 client for the event stream of Robot Server
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// wsConn is an established WebSocket connection
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWebsocket connects to the path of the server and completes handshake
+func (client *Client) dialWebsocket(ctx context.Context, path string) (*wsConn, error) {
+	target, err := url.Parse(client.BaseURL + path)
+	if err != nil {
+		return nil, err
+	}
+
+	host := target.Host
+	if target.Port() == "" {
+		if target.Scheme == "https" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: client.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Scheme == "https" {
+		config := &tls.Config{}
+		if client.TLS != nil {
+			config = client.TLS.Clone()
+		}
+		if config.ServerName == "" {
+			config.ServerName = target.Hostname()
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	// Handshake must not hang, stream itself has no deadline
+	conn.SetDeadline(time.Now().Add(client.Timeout))
+	defer conn.SetDeadline(time.Time{})
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	request := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n", target.RequestURI(), target.Host, key)
+	if client.Token != "" {
+		request += "Authorization: Bearer " + client.Token + "\r\n"
+	}
+	request += "\r\n"
+
+	if _, err := io.WriteString(conn, request); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		defer conn.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 4096))
+		return nil, &APIError{Status: response.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	if response.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(hash[:]) {
+		conn.Close()
+		return nil, errors.New("invalid WebSocket handshake")
+	}
+
+	return &wsConn{conn: conn, reader: reader}, nil
+}
+
+// writeFrame sends a single masked frame, client frames must be masked
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		header = append(header, 0x80|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 0x80|126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 0x80|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	mask := make([]byte, 4)
+	rand.Read(mask)
+	header = append(header, mask...)
+
+	masked := make([]byte, length)
+	for i := range payload {
+		masked[i] = payload[i] ^ mask[i%4]
+	}
+
+	_, err := ws.conn.Write(append(header, masked...))
+	return err
+}
+
+// ReadMessage returns payload of the next text message
+// Pings are answered, close frame ends the stream with io.EOF
+func (ws *wsConn) ReadMessage() ([]byte, error) {
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+			return nil, err
+		}
+
+		opcode := header[0] & 0x0F
+		length := uint64(header[1] & 0x7F)
+
+		switch length {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+				return nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+				return nil, err
+			}
+			length = binary.BigEndian.Uint64(ext[:])
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(ws.reader, payload); err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opText:
+			return payload, nil
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opClose:
+			ws.writeFrame(opClose, nil)
+			return nil, io.EOF
+		}
+	}
+}
+
+// Close finishes connection
+func (ws *wsConn) Close() error {
+	ws.writeFrame(opClose, nil)
+	return ws.conn.Close()
+}
+
+// EventStream receives events of the application
+type EventStream struct {
+	ws *wsConn
+}
+
+// Events subscribes to events of the types, no types means all events
+func (client *Client) Events(ctx context.Context, types ...string) (*EventStream, error) {
+	path := "/events"
+	if len(types) > 0 {
+		path += "?types=" + url.QueryEscape(strings.Join(types, ","))
+	}
+
+	ws, err := client.dialWebsocket(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{ws: ws}, nil
+}
+
+// Next waits for the next event, io.EOF means that server closed the stream
+func (stream *EventStream) Next() (*Event, error) {
+	message, err := stream.ws.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Event{}
+	if err := json.Unmarshal(message, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetTypes changes filter of the stream, no types means all events
+func (stream *EventStream) SetTypes(types ...string) error {
+	if types == nil {
+		types = []string{}
+	}
+	body, err := json.Marshal(map[string][]string{"types": types})
+	if err != nil {
+		return err
+	}
+	return stream.ws.writeFrame(opText, body)
+}
+
+// Close finishes the stream
+func (stream *EventStream) Close() error {
+	return stream.ws.Close()
+}
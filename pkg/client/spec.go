@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Operation is an endpoint of the API, which Client uses
+// Request and Body are examples of JSON request and response, they are checked against the server in tests
+type Operation struct {
+	Method  string
+	Path    string
+	Request interface{}
+	Body    interface{}
+}
+
+// operations lists every endpoint used by Client in OpenAPI path syntax
+var operations = []Operation{
+	{Method: "PUT", Path: "/{command}"},
+	{Method: "GET", Path: "/status", Body: Status{}},
+	{Method: "GET", Path: "/video"},
+	{Method: "GET", Path: "/events", Body: Event{}},
+	{Method: "GET", Path: "/metrics"},
+	{Method: "GET", Path: "/log", Body: map[string]string{}},
+	{Method: "POST", Path: "/log/{component}/{level}"},
+	{Method: "GET", Path: "/preflight", Body: PreflightReport{}},
+	{Method: "GET", Path: "/healthz", Body: HealthReport{}},
+	{Method: "GET", Path: "/readyz", Body: HealthReport{}},
+	{Method: "GET", Path: "/api/v1/snapshot"},
+	{Method: "POST", Path: "/api/v1/captures", Request: captureRequest{}, Body: CaptureStatus{}},
+	{Method: "GET", Path: "/api/v1/captures", Body: []CaptureStatus{}},
+	{Method: "GET", Path: "/api/v1/recording", Body: RecordingStatus{}},
+	{Method: "POST", Path: "/api/v1/recording/start", Request: recordingRequest{}, Body: RecordingStatus{}},
+	{Method: "POST", Path: "/api/v1/recording/stop", Body: RecordingStatus{}},
+	{Method: "GET", Path: "/api/v1/camera", Body: CameraState{}},
+	{Method: "POST", Path: "/api/v1/camera", Request: map[string]float64{}, Body: CameraUpdate{}},
+	{Method: "GET", Path: "/api/v1/preprocess", Body: PreprocessParams{}},
+	{Method: "POST", Path: "/api/v1/preprocess", Request: PreprocessParams{}, Body: PreprocessParams{}},
+	{Method: "GET", Path: "/api/v1/masks", Body: Masks{}},
+	{Method: "POST", Path: "/api/v1/masks", Request: Masks{}, Body: Masks{}},
+	{Method: "GET", Path: "/api/v1/quality", Body: QualityParams{}},
+	{Method: "POST", Path: "/api/v1/quality", Request: QualityParams{}, Body: QualityParams{}},
+	{Method: "GET", Path: "/api/v1/acquisition", Body: AcquisitionParams{}},
+	{Method: "POST", Path: "/api/v1/acquisition", Request: AcquisitionParams{}, Body: AcquisitionParams{}},
+	{Method: "GET", Path: "/api/v1/search", Body: SearchParams{}},
+	{Method: "POST", Path: "/api/v1/search", Request: SearchParams{}, Body: SearchParams{}},
+	{Method: "GET", Path: "/api/v1/shape", Body: ShapeThresholds{}},
+	{Method: "POST", Path: "/api/v1/shape", Request: ShapeThresholds{}, Body: ShapeThresholds{}},
+	{Method: "GET", Path: "/api/v1/grouping", Body: GroupingParams{}},
+	{Method: "POST", Path: "/api/v1/grouping", Request: GroupingParams{}, Body: GroupingParams{}},
+}
+
+// Operations returns every endpoint used by Client
+func Operations() []Operation {
+	return append([]Operation{}, operations...)
+}
+
+// Spec returns OpenAPI document of the server
+func (client *Client) Spec(ctx context.Context) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	if _, err := client.getJSON(ctx, "/openapi.json", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// VerifySpec checks that server provides every endpoint used by Client
+func (client *Client) VerifySpec(ctx context.Context) error {
+	spec, err := client.Spec(ctx)
+	if err != nil {
+		return err
+	}
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	var missing []string
+	for _, op := range operations {
+		methods, _ := paths[op.Path].(map[string]interface{})
+		if _, ok := methods[strings.ToLower(op.Method)]; !ok {
+			missing = append(missing, op.Method+" "+op.Path)
+		}
+	}
+
+	if len(missing) > 0 {
+		info, _ := spec["info"].(map[string]interface{})
+		return fmt.Errorf("server API version %v lacks %s", info["version"], strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package client
+
+import (
+	"encoding/json"
+	"image"
+	"time"
+)
+
+// Status is a snapshot of the application state
+type Status struct {
+	IsManual    bool                    `json:"manual"`
+	IsBlocked   bool                    `json:"blocked"`
+	CascadeType int                     `json:"cascade"`
+	Overlay     int                     `json:"overlay"`
+	Acquisition AcquisitionStatus       `json:"acquisition"`
+	Stages      map[string]StageCounter `json:"stages"`
+	Preflight   *PreflightReport        `json:"preflight,omitempty"`
+	Telemetry   Telemetry               `json:"telemetry"`
+}
+
+// AcquisitionStatus is a state of multi-frame target confirmation
+type AcquisitionStatus struct {
+	State     string          `json:"state"`
+	Confirmed int             `json:"confirmed"`
+	Required  int             `json:"required"`
+	Window    int             `json:"window"`
+	Target    image.Rectangle `json:"target"`
+}
+
+// StageCounter stores results of a single filtering stage
+type StageCounter struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// Telemetry is a latest driving state
+type Telemetry struct {
+	Throttle       int       `json:"throttle"`
+	Steering       int       `json:"steering"`
+	FPS            float64   `json:"fps"`
+	FailureCounter int       `json:"failure_counter"`
+	HasTarget      bool      `json:"has_target"`
+	Updated        time.Time `json:"updated"`
+}
+
+// PreflightCheck is a result of a single self-test step
+type PreflightCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// PreflightReport is a result of the self-test
+type PreflightReport struct {
+	Passed bool             `json:"passed"`
+	Time   time.Time        `json:"time"`
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// ComponentHealth is a health of a single subsystem
+type ComponentHealth struct {
+	Name   string                 `json:"name"`
+	Live   bool                   `json:"live"`
+	Ready  bool                   `json:"ready"`
+	Detail string                 `json:"detail,omitempty"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// HealthReport collects health of every subsystem
+type HealthReport struct {
+	Live       bool              `json:"live"`
+	Ready      bool              `json:"ready"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// SnapshotOptions selects frame and its encoding
+// Zero Width or Height keeps aspect ratio, both zero keep original size
+type SnapshotOptions struct {
+	// Raw selects frame without overlay
+	Raw bool
+	// PNG selects PNG encoding instead of JPEG
+	PNG    bool
+	Width  int
+	Height int
+}
+
+// CaptureStatus is a progress of capture job
+type CaptureStatus struct {
+	ID       string     `json:"id"`
+	Dir      string     `json:"dir"`
+	Format   string     `json:"format"`
+	State    string     `json:"state"`
+	Frames   int        `json:"frames"`
+	Saved    int        `json:"saved"`
+	Skipped  int        `json:"skipped"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// captureRequest is a body of the capture request
+type captureRequest struct {
+	Frames int    `json:"frames"`
+	Format string `json:"format,omitempty"`
+}
+
+// RecordingOptions describes video recording, zero values select defaults of the server
+type RecordingOptions struct {
+	// MaxDuration is a length of a single file, it is rounded to seconds
+	MaxDuration time.Duration
+	FPS         float64
+}
+
+// recordingRequest is a body of the recording start request
+type recordingRequest struct {
+	MaxDuration int     `json:"max_duration_s,omitempty"`
+	FPS         float64 `json:"fps,omitempty"`
+}
+
+// RecordingStatus describes the current or the last video recording
+type RecordingStatus struct {
+	Recording   bool       `json:"recording"`
+	Dir         string     `json:"dir"`
+	File        string     `json:"file,omitempty"`
+	Files       []string   `json:"files"`
+	Frames      int        `json:"frames"`
+	Skipped     int        `json:"skipped"`
+	FPS         float64    `json:"fps"`
+	MaxDuration int        `json:"max_duration_s"`
+	Started     *time.Time `json:"started,omitempty"`
+	Finished    *time.Time `json:"finished,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// CameraProperty is a value of capture property reported by the camera after change
+type CameraProperty struct {
+	Name      string  `json:"name"`
+	Requested float64 `json:"requested"`
+	Actual    float64 `json:"actual"`
+	Applied   bool    `json:"applied"`
+}
+
+// CameraUpdate is a result of changing properties, rejected properties keep previous values
+type CameraUpdate struct {
+	Device     string           `json:"device"`
+	Applied    bool             `json:"applied"`
+	Properties []CameraProperty `json:"properties"`
+}
+
+// CameraState describes the camera and its saved profile
+type CameraState struct {
+	Device     string             `json:"device"`
+	Open       bool               `json:"open"`
+	Properties map[string]float64 `json:"properties"`
+	Profile    map[string]float64 `json:"profile"`
+}
+
+// PreprocessParams are steps applied to the frame before detection, in order of fields
+type PreprocessParams struct {
+	Undistort struct {
+		Enabled bool    `json:"enabled"`
+		Fx      float64 `json:"fx"`
+		Fy      float64 `json:"fy"`
+		Cx      float64 `json:"cx"`
+		Cy      float64 `json:"cy"`
+		K1      float64 `json:"k1"`
+		K2      float64 `json:"k2"`
+		P1      float64 `json:"p1"`
+		P2      float64 `json:"p2"`
+		K3      float64 `json:"k3"`
+	} `json:"undistort"`
+	Crop struct {
+		Enabled bool    `json:"enabled"`
+		X       float64 `json:"x"`
+		Y       float64 `json:"y"`
+		Width   float64 `json:"width"`
+		Height  float64 `json:"height"`
+	} `json:"crop"`
+	Resize struct {
+		Enabled bool    `json:"enabled"`
+		Scale   float64 `json:"scale"`
+	} `json:"resize"`
+	Grayscale struct {
+		Enabled bool `json:"enabled"`
+	} `json:"grayscale"`
+	CLAHE struct {
+		Enabled   bool    `json:"enabled"`
+		ClipLimit float64 `json:"clip_limit"`
+		TileSize  int     `json:"tile_size"`
+	} `json:"clahe"`
+	Gamma struct {
+		Enabled bool    `json:"enabled"`
+		Gamma   float64 `json:"gamma"`
+	} `json:"gamma"`
+	Blur struct {
+		Enabled    bool    `json:"enabled"`
+		KernelSize int     `json:"kernel_size"`
+		Sigma      float64 `json:"sigma"`
+	} `json:"blur"`
+}
+
+// NormPoint is a point in normalised frame coordinates, 0..1 from top left corner
+type NormPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Zone is a named polygon in normalised frame coordinates
+type Zone struct {
+	Name    string      `json:"name,omitempty"`
+	Polygon []NormPoint `json:"polygon"`
+}
+
+// Masks restrict detection to include zones and reject candidates inside exclude zones
+type Masks struct {
+	Include []Zone `json:"include"`
+	Exclude []Zone `json:"exclude"`
+}
+
+// QualityParams are thresholds of the frame quality gate
+type QualityParams struct {
+	Enabled        bool    `json:"enabled"`
+	MinSharpness   float64 `json:"min_sharpness"`
+	MinBrightness  float64 `json:"min_brightness"`
+	MaxBrightness  float64 `json:"max_brightness"`
+	MaxOverexposed float64 `json:"max_overexposed"`
+	MaxHold        int     `json:"max_hold_ms"`
+}
+
+// FrameQuality describes sharpness and exposure of a frame
+type FrameQuality struct {
+	Sharpness   float64 `json:"sharpness"`
+	Brightness  float64 `json:"brightness"`
+	Overexposed float64 `json:"overexposed"`
+	Usable      bool    `json:"usable"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// AcquisitionParams configure confirmation of the target on Required of the last Window frames
+type AcquisitionParams struct {
+	Required    int     `json:"required"`
+	Window      int     `json:"window"`
+	MaxDistance float64 `json:"max_distance"`
+}
+
+// SearchParams configure search window around the previous target
+type SearchParams struct {
+	Enabled       bool    `json:"enabled"`
+	Expand        float64 `json:"expand"`
+	ScaleRange    float64 `json:"scale_range"`
+	FullScanEvery int     `json:"full_scan_every"`
+}
+
+// ShapeParams describe expected contour geometry of a sign, zero Vertices select a circle
+type ShapeParams struct {
+	Enabled         bool    `json:"enabled"`
+	Vertices        int     `json:"vertices"`
+	VertexTolerance int     `json:"vertex_tolerance"`
+	Epsilon         float64 `json:"epsilon"`
+	MinAreaRatio    float64 `json:"min_area_ratio"`
+	MinRadiusRatio  float64 `json:"min_radius_ratio"`
+}
+
+// ShapeThresholds are contour geometry params of every sign
+type ShapeThresholds struct {
+	Stop   ShapeParams `json:"stop"`
+	Circle ShapeParams `json:"circle"`
+	Yield  ShapeParams `json:"yield"`
+}
+
+// GroupingParams configure merging of overlapping cascade hits
+type GroupingParams struct {
+	Enabled            bool    `json:"enabled"`
+	UseGroupRectangles bool    `json:"use_group_rectangles"`
+	GroupThreshold     int     `json:"group_threshold"`
+	GroupEps           float64 `json:"group_eps"`
+	IoUThreshold       float64 `json:"iou_threshold"`
+	MergeBoxes         bool    `json:"merge_boxes"`
+}
+
+// Event types
+const (
+	EventFrame    = "frame"
+	EventCommand  = "command"
+	EventMode     = "mode"
+	EventError    = "error"
+	EventWatchdog = "watchdog"
+)
+
+// Event is a message of the event stream
+// Data is decoded by Decode according to Type
+type Event struct {
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Decode unmarshals data of the event, e.g. into FrameEvent
+func (event *Event) Decode(res interface{}) error {
+	return json.Unmarshal(event.Data, res)
+}
+
+// FrameEvent describes detections of a processed frame
+type FrameEvent struct {
+	Frame     uint64            `json:"frame"`
+	Seq       uint64            `json:"seq"`
+	Class     string            `json:"class"`
+	Raw       []image.Rectangle `json:"raw"`
+	Near      []image.Rectangle `json:"near"`
+	Scored    []ScoredEvent     `json:"scored"`
+	Target    *image.Rectangle  `json:"target,omitempty"`
+	Throttle  int               `json:"throttle"`
+	Steering  int               `json:"steering"`
+	Failures  int               `json:"failures"`
+	Quality   FrameQuality      `json:"quality"`
+	Scan      string            `json:"scan,omitempty"`
+	Processed time.Duration     `json:"processed_ns"`
+}
+
+// ScoredEvent is a candidate with its similarity score
+type ScoredEvent struct {
+	Rect    image.Rectangle `json:"rect"`
+	Score   float64         `json:"score"`
+	Trusted bool            `json:"trusted"`
+}
+
+// CommandEvent describes a command sent to the car
+type CommandEvent struct {
+	Command string `json:"command"`
+}
+
+// ModeEvent describes change of the state machine
+type ModeEvent struct {
+	Manual  bool   `json:"manual"`
+	Blocked bool   `json:"blocked"`
+	Cascade string `json:"cascade"`
+	Overlay int    `json:"overlay"`
+}
+
+// MessageEvent describes an error or a watchdog trip
+type MessageEvent struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,4 @@
+// Package robotpb contains gRPC stubs of the Robot Server generated from robot.proto
+package robotpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative robot.proto
@@ -0,0 +1,480 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: robot.proto
+
+package robotpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RobotControlClient is the client API for RobotControl service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RobotControlClient interface {
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error)
+	SetMode(ctx context.Context, in *SetModeRequest, opts ...grpc.CallOption) (*CommandReply, error)
+	SetBlocked(ctx context.Context, in *SetBlockedRequest, opts ...grpc.CallOption) (*CommandReply, error)
+	SetSign(ctx context.Context, in *SetSignRequest, opts ...grpc.CallOption) (*CommandReply, error)
+	SetOverlay(ctx context.Context, in *SetOverlayRequest, opts ...grpc.CallOption) (*CommandReply, error)
+	Drive(ctx context.Context, in *DriveRequest, opts ...grpc.CallOption) (*CommandReply, error)
+	StreamTelemetry(ctx context.Context, in *StreamTelemetryRequest, opts ...grpc.CallOption) (RobotControl_StreamTelemetryClient, error)
+	StreamDetections(ctx context.Context, in *StreamDetectionsRequest, opts ...grpc.CallOption) (RobotControl_StreamDetectionsClient, error)
+	Joystick(ctx context.Context, opts ...grpc.CallOption) (RobotControl_JoystickClient, error)
+}
+
+type robotControlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRobotControlClient(cc grpc.ClientConnInterface) RobotControlClient {
+	return &robotControlClient{cc}
+}
+
+func (c *robotControlClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	out := new(Status)
+	err := c.cc.Invoke(ctx, "/robot.v1.RobotControl/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotControlClient) SetMode(ctx context.Context, in *SetModeRequest, opts ...grpc.CallOption) (*CommandReply, error) {
+	out := new(CommandReply)
+	err := c.cc.Invoke(ctx, "/robot.v1.RobotControl/SetMode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotControlClient) SetBlocked(ctx context.Context, in *SetBlockedRequest, opts ...grpc.CallOption) (*CommandReply, error) {
+	out := new(CommandReply)
+	err := c.cc.Invoke(ctx, "/robot.v1.RobotControl/SetBlocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotControlClient) SetSign(ctx context.Context, in *SetSignRequest, opts ...grpc.CallOption) (*CommandReply, error) {
+	out := new(CommandReply)
+	err := c.cc.Invoke(ctx, "/robot.v1.RobotControl/SetSign", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotControlClient) SetOverlay(ctx context.Context, in *SetOverlayRequest, opts ...grpc.CallOption) (*CommandReply, error) {
+	out := new(CommandReply)
+	err := c.cc.Invoke(ctx, "/robot.v1.RobotControl/SetOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotControlClient) Drive(ctx context.Context, in *DriveRequest, opts ...grpc.CallOption) (*CommandReply, error) {
+	out := new(CommandReply)
+	err := c.cc.Invoke(ctx, "/robot.v1.RobotControl/Drive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotControlClient) StreamTelemetry(ctx context.Context, in *StreamTelemetryRequest, opts ...grpc.CallOption) (RobotControl_StreamTelemetryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RobotControl_ServiceDesc.Streams[0], "/robot.v1.RobotControl/StreamTelemetry", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &robotControlStreamTelemetryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RobotControl_StreamTelemetryClient interface {
+	Recv() (*Telemetry, error)
+	grpc.ClientStream
+}
+
+type robotControlStreamTelemetryClient struct {
+	grpc.ClientStream
+}
+
+func (x *robotControlStreamTelemetryClient) Recv() (*Telemetry, error) {
+	m := new(Telemetry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *robotControlClient) StreamDetections(ctx context.Context, in *StreamDetectionsRequest, opts ...grpc.CallOption) (RobotControl_StreamDetectionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RobotControl_ServiceDesc.Streams[1], "/robot.v1.RobotControl/StreamDetections", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &robotControlStreamDetectionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RobotControl_StreamDetectionsClient interface {
+	Recv() (*Detection, error)
+	grpc.ClientStream
+}
+
+type robotControlStreamDetectionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *robotControlStreamDetectionsClient) Recv() (*Detection, error) {
+	m := new(Detection)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *robotControlClient) Joystick(ctx context.Context, opts ...grpc.CallOption) (RobotControl_JoystickClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RobotControl_ServiceDesc.Streams[2], "/robot.v1.RobotControl/Joystick", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &robotControlJoystickClient{stream}
+	return x, nil
+}
+
+type RobotControl_JoystickClient interface {
+	Send(*DriveRequest) error
+	Recv() (*DriveAck, error)
+	grpc.ClientStream
+}
+
+type robotControlJoystickClient struct {
+	grpc.ClientStream
+}
+
+func (x *robotControlJoystickClient) Send(m *DriveRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *robotControlJoystickClient) Recv() (*DriveAck, error) {
+	m := new(DriveAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RobotControlServer is the server API for RobotControl service.
+// All implementations must embed UnimplementedRobotControlServer
+// for forward compatibility
+type RobotControlServer interface {
+	GetStatus(context.Context, *GetStatusRequest) (*Status, error)
+	SetMode(context.Context, *SetModeRequest) (*CommandReply, error)
+	SetBlocked(context.Context, *SetBlockedRequest) (*CommandReply, error)
+	SetSign(context.Context, *SetSignRequest) (*CommandReply, error)
+	SetOverlay(context.Context, *SetOverlayRequest) (*CommandReply, error)
+	Drive(context.Context, *DriveRequest) (*CommandReply, error)
+	StreamTelemetry(*StreamTelemetryRequest, RobotControl_StreamTelemetryServer) error
+	StreamDetections(*StreamDetectionsRequest, RobotControl_StreamDetectionsServer) error
+	Joystick(RobotControl_JoystickServer) error
+	mustEmbedUnimplementedRobotControlServer()
+}
+
+// UnimplementedRobotControlServer must be embedded to have forward compatible implementations.
+type UnimplementedRobotControlServer struct {
+}
+
+func (UnimplementedRobotControlServer) GetStatus(context.Context, *GetStatusRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedRobotControlServer) SetMode(context.Context, *SetModeRequest) (*CommandReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMode not implemented")
+}
+func (UnimplementedRobotControlServer) SetBlocked(context.Context, *SetBlockedRequest) (*CommandReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBlocked not implemented")
+}
+func (UnimplementedRobotControlServer) SetSign(context.Context, *SetSignRequest) (*CommandReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSign not implemented")
+}
+func (UnimplementedRobotControlServer) SetOverlay(context.Context, *SetOverlayRequest) (*CommandReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOverlay not implemented")
+}
+func (UnimplementedRobotControlServer) Drive(context.Context, *DriveRequest) (*CommandReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drive not implemented")
+}
+func (UnimplementedRobotControlServer) StreamTelemetry(*StreamTelemetryRequest, RobotControl_StreamTelemetryServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTelemetry not implemented")
+}
+func (UnimplementedRobotControlServer) StreamDetections(*StreamDetectionsRequest, RobotControl_StreamDetectionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDetections not implemented")
+}
+func (UnimplementedRobotControlServer) Joystick(RobotControl_JoystickServer) error {
+	return status.Errorf(codes.Unimplemented, "method Joystick not implemented")
+}
+func (UnimplementedRobotControlServer) mustEmbedUnimplementedRobotControlServer() {}
+
+// UnsafeRobotControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RobotControlServer will
+// result in compilation errors.
+type UnsafeRobotControlServer interface {
+	mustEmbedUnimplementedRobotControlServer()
+}
+
+func RegisterRobotControlServer(s grpc.ServiceRegistrar, srv RobotControlServer) {
+	s.RegisterService(&RobotControl_ServiceDesc, srv)
+}
+
+func _RobotControl_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotControlServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robot.v1.RobotControl/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotControlServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotControl_SetMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotControlServer).SetMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robot.v1.RobotControl/SetMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotControlServer).SetMode(ctx, req.(*SetModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotControl_SetBlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBlockedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotControlServer).SetBlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robot.v1.RobotControl/SetBlocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotControlServer).SetBlocked(ctx, req.(*SetBlockedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotControl_SetSign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotControlServer).SetSign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robot.v1.RobotControl/SetSign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotControlServer).SetSign(ctx, req.(*SetSignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotControl_SetOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOverlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotControlServer).SetOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robot.v1.RobotControl/SetOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotControlServer).SetOverlay(ctx, req.(*SetOverlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotControl_Drive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DriveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotControlServer).Drive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robot.v1.RobotControl/Drive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotControlServer).Drive(ctx, req.(*DriveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotControl_StreamTelemetry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTelemetryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RobotControlServer).StreamTelemetry(m, &robotControlStreamTelemetryServer{stream})
+}
+
+type RobotControl_StreamTelemetryServer interface {
+	Send(*Telemetry) error
+	grpc.ServerStream
+}
+
+type robotControlStreamTelemetryServer struct {
+	grpc.ServerStream
+}
+
+func (x *robotControlStreamTelemetryServer) Send(m *Telemetry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RobotControl_StreamDetections_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamDetectionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RobotControlServer).StreamDetections(m, &robotControlStreamDetectionsServer{stream})
+}
+
+type RobotControl_StreamDetectionsServer interface {
+	Send(*Detection) error
+	grpc.ServerStream
+}
+
+type robotControlStreamDetectionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *robotControlStreamDetectionsServer) Send(m *Detection) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RobotControl_Joystick_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RobotControlServer).Joystick(&robotControlJoystickServer{stream})
+}
+
+type RobotControl_JoystickServer interface {
+	Send(*DriveAck) error
+	Recv() (*DriveRequest, error)
+	grpc.ServerStream
+}
+
+type robotControlJoystickServer struct {
+	grpc.ServerStream
+}
+
+func (x *robotControlJoystickServer) Send(m *DriveAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *robotControlJoystickServer) Recv() (*DriveRequest, error) {
+	m := new(DriveRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RobotControl_ServiceDesc is the grpc.ServiceDesc for RobotControl service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RobotControl_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "robot.v1.RobotControl",
+	HandlerType: (*RobotControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _RobotControl_GetStatus_Handler,
+		},
+		{
+			MethodName: "SetMode",
+			Handler:    _RobotControl_SetMode_Handler,
+		},
+		{
+			MethodName: "SetBlocked",
+			Handler:    _RobotControl_SetBlocked_Handler,
+		},
+		{
+			MethodName: "SetSign",
+			Handler:    _RobotControl_SetSign_Handler,
+		},
+		{
+			MethodName: "SetOverlay",
+			Handler:    _RobotControl_SetOverlay_Handler,
+		},
+		{
+			MethodName: "Drive",
+			Handler:    _RobotControl_Drive_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTelemetry",
+			Handler:       _RobotControl_StreamTelemetry_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamDetections",
+			Handler:       _RobotControl_StreamDetections_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Joystick",
+			Handler:       _RobotControl_Joystick_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "robot.proto",
+}